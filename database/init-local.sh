@@ -0,0 +1,15 @@
+#!/bin/sh
+# Initialises the local Postgres container: applies the migrations as the
+# owner, then lets the service log in as credit_scoring_app so that row-level
+# security applies to it as it would in production.
+set -e
+
+for file in /migrations/*.sql; do
+    echo "Applying $file..."
+    psql -v ON_ERROR_STOP=1 -U "$POSTGRES_USER" -d "$POSTGRES_DB" -f "$file"
+done
+
+psql -v ON_ERROR_STOP=1 -U "$POSTGRES_USER" -d "$POSTGRES_DB" \
+    -v password="$CREDIT_SCORING_DB_PASSWORD" <<'EOSQL'
+ALTER ROLE credit_scoring_app LOGIN PASSWORD :'password';
+EOSQL
@@ -0,0 +1,25 @@
+-- Migration: Create credit_score_inputs table
+-- Version: 006
+-- Description: Latest scoring inputs per user, used to recalculate scores from upstream events
+
+CREATE TABLE IF NOT EXISTS credit_score_inputs (
+    user_id VARCHAR(255) PRIMARY KEY,
+    income_amount DECIMAL(15,2) NOT NULL CHECK (income_amount >= 0),
+    employment_status VARCHAR(50) NOT NULL,
+    account_age INTEGER NOT NULL CHECK (account_age >= 0),
+    transaction_data JSONB NOT NULL DEFAULT '{}',
+    loan_history JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Trigger
+CREATE TRIGGER update_credit_score_inputs_updated_at
+    BEFORE UPDATE ON credit_score_inputs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Comments
+COMMENT ON TABLE credit_score_inputs IS 'Latest inputs used to calculate each user''s credit score';
+COMMENT ON COLUMN credit_score_inputs.account_age IS 'Account age in months as of updated_at';
+COMMENT ON COLUMN credit_score_inputs.loan_history IS 'JSON array of loan history items';
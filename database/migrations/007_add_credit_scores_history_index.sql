@@ -0,0 +1,9 @@
+-- Migration: Add keyset pagination index on credit_scores
+-- Version: 007
+-- Description: Supports cursor-based history queries ordered by (calculated_at, id)
+
+CREATE INDEX IF NOT EXISTS idx_credit_scores_user_calculated_id
+    ON credit_scores(user_id, calculated_at DESC, id DESC);
+
+-- Superseded by idx_credit_scores_user_calculated_id
+DROP INDEX IF EXISTS idx_credit_scores_user_calculated;
@@ -0,0 +1,24 @@
+-- Migration: Create idempotency_keys table
+-- Version: 008
+-- Description: Stored responses for requests sent with an Idempotency-Key header
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key VARCHAR(255) NOT NULL,
+    principal VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code INTEGER NOT NULL,
+    response_body BYTEA NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (idempotency_key, principal)
+);
+
+-- Indexes
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+-- Comments
+COMMENT ON TABLE idempotency_keys IS 'Responses replayed for retried requests with the same Idempotency-Key';
+COMMENT ON COLUMN idempotency_keys.principal IS 'Authenticated caller the key is scoped to';
+COMMENT ON COLUMN idempotency_keys.request_hash IS 'SHA-256 of method, path and body; reuse with a different hash is rejected';
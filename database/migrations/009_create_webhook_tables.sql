@@ -0,0 +1,58 @@
+-- Migration: Create webhook tables
+-- Version: 009
+-- Description: Webhook subscriptions for score events. Deliveries are stored in
+-- notifications with notification_type 'WEBHOOK'; each attempt is logged.
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id VARCHAR(255) PRIMARY KEY,
+    owner_id VARCHAR(255) NOT NULL,
+    url VARCHAR(255) NOT NULL,
+    event_types TEXT[] NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Deliveries
+ALTER TABLE notifications
+    ADD COLUMN IF NOT EXISTS webhook_subscription_id VARCHAR(255) REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    notification_id VARCHAR(255) NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER,
+    response_body TEXT,
+    error TEXT,
+    duration_ms INTEGER NOT NULL,
+    attempted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Expiry events
+ALTER TABLE credit_scores
+    ADD COLUMN IF NOT EXISTS expiry_notified_at TIMESTAMP;
+
+-- Indexes
+CREATE INDEX idx_webhook_subscriptions_owner_id ON webhook_subscriptions(owner_id);
+CREATE INDEX idx_webhook_subscriptions_active ON webhook_subscriptions(active) WHERE active;
+CREATE INDEX idx_notifications_webhook_due ON notifications(next_attempt_at)
+    WHERE notification_type = 'WEBHOOK' AND status = 'PENDING';
+CREATE INDEX idx_notifications_webhook_subscription ON notifications(webhook_subscription_id, created_at DESC);
+CREATE INDEX idx_webhook_delivery_attempts_notification_id ON webhook_delivery_attempts(notification_id, attempt);
+CREATE INDEX idx_credit_scores_expiry_pending ON credit_scores(expires_at) WHERE expiry_notified_at IS NULL;
+
+-- Trigger
+CREATE TRIGGER update_webhook_subscriptions_updated_at
+    BEFORE UPDATE ON webhook_subscriptions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Comments
+COMMENT ON TABLE webhook_subscriptions IS 'Partner callbacks for credit score events';
+COMMENT ON COLUMN webhook_subscriptions.owner_id IS 'User who registered the subscription; only they can manage it';
+COMMENT ON COLUMN webhook_subscriptions.secret IS 'HMAC-SHA256 key used to sign deliveries';
+COMMENT ON COLUMN notifications.next_attempt_at IS 'When a pending webhook delivery is next attempted';
+COMMENT ON TABLE webhook_delivery_attempts IS 'Log of every webhook delivery attempt';
+COMMENT ON COLUMN credit_scores.expiry_notified_at IS 'When the credit_score_expired event was emitted';
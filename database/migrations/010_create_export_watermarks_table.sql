@@ -0,0 +1,28 @@
+-- Migration: Create export_watermarks table
+-- Version: 010
+-- Description: Progress of incremental credit_scores exports to the data warehouse
+
+CREATE TABLE IF NOT EXISTS export_watermarks (
+    job VARCHAR(100) PRIMARY KEY,
+    last_created_at TIMESTAMP NOT NULL,
+    last_id VARCHAR(255) NOT NULL,
+    last_location TEXT NOT NULL,
+    last_row_count BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indexes
+-- Exports page through credit_scores in (created_at, id) order
+CREATE INDEX idx_credit_scores_created_id ON credit_scores(created_at, id);
+
+-- Trigger
+CREATE TRIGGER update_export_watermarks_updated_at
+    BEFORE UPDATE ON export_watermarks
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Comments
+COMMENT ON TABLE export_watermarks IS 'Last credit_scores row exported by each incremental export job';
+COMMENT ON COLUMN export_watermarks.last_created_at IS 'created_at of the last exported row; with last_id, the next run starts after it';
+COMMENT ON COLUMN export_watermarks.last_location IS 'Where the last non-empty export was written';
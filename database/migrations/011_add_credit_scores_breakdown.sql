@@ -0,0 +1,13 @@
+-- Migration: Add scoring breakdown to credit_scores
+-- Version: 011
+-- Description: Model version, sub-scores and reason codes returned by the v2 API
+
+ALTER TABLE credit_scores
+    ADD COLUMN IF NOT EXISTS model_version VARCHAR(50) NOT NULL DEFAULT 'cs-1.0',
+    ADD COLUMN IF NOT EXISTS sub_scores JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS reason_codes TEXT[] NOT NULL DEFAULT '{}';
+
+-- Comments
+COMMENT ON COLUMN credit_scores.model_version IS 'Scoring model that produced the score; rows before this migration are cs-1.0';
+COMMENT ON COLUMN credit_scores.sub_scores IS 'Weighted component scores; empty for rows scored before this migration';
+COMMENT ON COLUMN credit_scores.reason_codes IS 'Adverse reason codes, most impactful first';
@@ -0,0 +1,44 @@
+-- Migration: Create access grant and audit tables
+-- Version: 012
+-- Description: Delegation grants that let a user share their credit data with
+-- another user, and the audit log of access to another user's credit data
+
+CREATE TABLE IF NOT EXISTS access_grants (
+    id VARCHAR(255) PRIMARY KEY,
+    owner_id VARCHAR(255) NOT NULL,
+    grantee_id VARCHAR(255) NOT NULL,
+    permissions TEXT[] NOT NULL,
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (owner_id <> grantee_id)
+);
+
+CREATE TABLE IF NOT EXISTS access_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id VARCHAR(255) NOT NULL,
+    actor_role VARCHAR(50) NOT NULL,
+    subject_user_id VARCHAR(255) NOT NULL,
+    permission VARCHAR(50) NOT NULL,
+    basis VARCHAR(20),
+    grant_id VARCHAR(255) REFERENCES access_grants(id) ON DELETE SET NULL,
+    allowed BOOLEAN NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    request_id VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indexes
+-- Access checks look up the grantee's active grants from the owner
+CREATE INDEX idx_access_grants_owner_grantee ON access_grants(owner_id, grantee_id) WHERE revoked_at IS NULL;
+CREATE INDEX idx_access_grants_owner_id ON access_grants(owner_id, created_at DESC);
+CREATE INDEX idx_access_audit_log_subject ON access_audit_log(subject_user_id, created_at DESC);
+CREATE INDEX idx_access_audit_log_actor ON access_audit_log(actor_id, created_at DESC);
+
+-- Comments
+COMMENT ON TABLE access_grants IS 'Delegations from a user to another user to act on their credit data';
+COMMENT ON COLUMN access_grants.permissions IS 'Delegated permissions, e.g. score:read';
+COMMENT ON COLUMN access_grants.expires_at IS 'When the grant lapses; NULL grants last until revoked';
+COMMENT ON TABLE access_audit_log IS 'Every attempt to act on another user''s credit data, allowed or not';
+COMMENT ON COLUMN access_audit_log.basis IS 'Why access was allowed: staff or delegation; NULL when denied';
+COMMENT ON COLUMN access_audit_log.action IS 'Route or gRPC method, e.g. GET /api/v1/credit/score/:userId';
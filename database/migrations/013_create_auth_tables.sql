@@ -0,0 +1,62 @@
+-- Migration: Create auth tables
+-- Version: 013
+-- Description: Users and API clients that can obtain tokens from this service,
+-- and the refresh tokens issued to users
+
+CREATE TABLE IF NOT EXISTS users (
+    id VARCHAR(255) PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS api_clients (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    secret_hash CHAR(64) NOT NULL,
+    role VARCHAR(50) NOT NULL DEFAULT 'service',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id VARCHAR(255) PRIMARY KEY,
+    session_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indexes
+CREATE UNIQUE INDEX idx_users_email ON users(LOWER(email));
+CREATE UNIQUE INDEX idx_refresh_tokens_token_hash ON refresh_tokens(token_hash);
+CREATE INDEX idx_refresh_tokens_session_id ON refresh_tokens(session_id);
+CREATE INDEX idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
+
+-- Triggers
+CREATE TRIGGER update_users_updated_at
+    BEFORE UPDATE ON users
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_api_clients_updated_at
+    BEFORE UPDATE ON api_clients
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Comments
+COMMENT ON TABLE users IS 'Users who log in with a password; id matches the userId used across the platform';
+COMMENT ON COLUMN users.password_hash IS 'bcrypt hash';
+COMMENT ON TABLE api_clients IS 'Backend integrations that obtain tokens with client credentials';
+COMMENT ON COLUMN api_clients.secret_hash IS 'Hex SHA-256 of the client secret';
+COMMENT ON TABLE refresh_tokens IS 'Refresh tokens; each use rotates the token within its session';
+COMMENT ON COLUMN refresh_tokens.session_id IS 'Login session the token belongs to; reusing a rotated token revokes the whole session';
+COMMENT ON COLUMN refresh_tokens.token_hash IS 'Hex SHA-256 of the token';
+COMMENT ON COLUMN refresh_tokens.used_at IS 'When the token was exchanged for a new one';
@@ -0,0 +1,38 @@
+-- Migration: Create API keys table
+-- Version: 014
+-- Description: API keys that partners use instead of tokens, each bound to a
+-- tenant and limited to its scopes and request quota
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    tenant_id VARCHAR(255) NOT NULL,
+    key_prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    scopes TEXT[] NOT NULL,
+    rate_limit INTEGER NOT NULL CHECK (rate_limit > 0),
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indexes
+CREATE UNIQUE INDEX idx_api_keys_key_hash ON api_keys(key_hash);
+CREATE INDEX idx_api_keys_tenant_id ON api_keys(tenant_id, created_at DESC);
+
+-- Triggers
+CREATE TRIGGER update_api_keys_updated_at
+    BEFORE UPDATE ON api_keys
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Comments
+COMMENT ON TABLE api_keys IS 'Partner API keys, sent in the X-API-Key header';
+COMMENT ON COLUMN api_keys.key_prefix IS 'First characters of the key, to tell keys apart without storing them';
+COMMENT ON COLUMN api_keys.key_hash IS 'Hex SHA-256 of the key';
+COMMENT ON COLUMN api_keys.scopes IS 'What the key may do: score:write, score:read, history:read';
+COMMENT ON COLUMN api_keys.rate_limit IS 'Requests per minute the key may make';
+COMMENT ON COLUMN api_keys.last_used_at IS 'Updated at most once a minute';
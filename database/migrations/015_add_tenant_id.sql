@@ -0,0 +1,64 @@
+-- Migration: Add tenants
+-- Version: 015
+-- Description: Tenants with their scoring models, and the tenant each row
+-- belongs to. Existing rows move to the 'default' tenant.
+
+CREATE TABLE IF NOT EXISTS tenants (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    scoring_model JSONB,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO tenants (id, name) VALUES ('default', 'Default') ON CONFLICT (id) DO NOTHING;
+
+-- The defaults only backfill existing rows; the service always sets tenant_id
+ALTER TABLE credit_scores ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(255) NOT NULL DEFAULT 'default';
+ALTER TABLE credit_score_inputs ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(255) NOT NULL DEFAULT 'default';
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(255) NOT NULL DEFAULT 'default';
+ALTER TABLE webhook_subscriptions ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(255) NOT NULL DEFAULT 'default';
+ALTER TABLE access_grants ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(255) NOT NULL DEFAULT 'default';
+ALTER TABLE access_audit_log ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(255) NOT NULL DEFAULT 'default';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(255) NOT NULL DEFAULT 'default';
+ALTER TABLE api_clients ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(255) NOT NULL DEFAULT 'default';
+
+ALTER TABLE credit_scores ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE credit_score_inputs ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE idempotency_keys ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE webhook_subscriptions ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE access_grants ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE access_audit_log ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE api_clients ALTER COLUMN tenant_id DROP DEFAULT;
+
+-- The same user ID or idempotency key may appear in several tenants
+ALTER TABLE credit_score_inputs DROP CONSTRAINT credit_score_inputs_pkey;
+ALTER TABLE credit_score_inputs ADD PRIMARY KEY (tenant_id, user_id);
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (tenant_id, idempotency_key, principal);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_credit_scores_tenant_user_calculated_id
+    ON credit_scores(tenant_id, user_id, calculated_at DESC, id DESC);
+CREATE INDEX idx_webhook_subscriptions_tenant_owner ON webhook_subscriptions(tenant_id, owner_id);
+CREATE INDEX idx_access_grants_tenant_owner_grantee ON access_grants(tenant_id, owner_id, grantee_id) WHERE revoked_at IS NULL;
+CREATE INDEX idx_access_audit_log_tenant_subject ON access_audit_log(tenant_id, subject_user_id, created_at DESC);
+
+-- Superseded by the tenant-scoped indexes above
+DROP INDEX IF EXISTS idx_credit_scores_user_calculated_id;
+DROP INDEX IF EXISTS idx_webhook_subscriptions_owner_id;
+DROP INDEX IF EXISTS idx_access_grants_owner_grantee;
+
+-- Triggers
+CREATE TRIGGER update_tenants_updated_at
+    BEFORE UPDATE ON tenants
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Comments
+COMMENT ON TABLE tenants IS 'Lending partners served by the deployment';
+COMMENT ON COLUMN tenants.scoring_model IS 'Weights, version and validity of the tenant''s scoring model; NULL uses the built-in model';
+COMMENT ON COLUMN credit_scores.tenant_id IS 'Tenant the score belongs to; rows before this migration are default';
+COMMENT ON COLUMN users.tenant_id IS 'Tenant the user''s tokens act for';
+COMMENT ON COLUMN api_clients.tenant_id IS 'Tenant the client''s tokens act for';
@@ -0,0 +1,53 @@
+-- Migration: Enable row-level security on tenant-scoped tables
+-- Version: 016
+-- Description: Rows are only visible to transactions that set app.tenant_id to
+-- their tenant, or app.all_tenants to on for cross-tenant jobs, so a query
+-- missing its tenant_id filter can't reach another tenant's data.
+-- Superusers bypass these policies; the service must connect as an ordinary
+-- role. Later data migrations must SET app.all_tenants = 'on' to see any rows.
+
+CREATE OR REPLACE FUNCTION tenant_visible(row_tenant_id VARCHAR)
+RETURNS BOOLEAN AS $$
+    SELECT row_tenant_id = current_setting('app.tenant_id', true)
+        OR current_setting('app.all_tenants', true) = 'on';
+$$ LANGUAGE sql STABLE;
+
+-- FORCE applies the policies to the tables' owner too
+ALTER TABLE credit_scores ENABLE ROW LEVEL SECURITY;
+ALTER TABLE credit_scores FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON credit_scores
+    USING (tenant_visible(tenant_id))
+    WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE credit_score_inputs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE credit_score_inputs FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON credit_score_inputs
+    USING (tenant_visible(tenant_id))
+    WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE idempotency_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE idempotency_keys FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON idempotency_keys
+    USING (tenant_visible(tenant_id))
+    WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE webhook_subscriptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_subscriptions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON webhook_subscriptions
+    USING (tenant_visible(tenant_id))
+    WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE access_grants ENABLE ROW LEVEL SECURITY;
+ALTER TABLE access_grants FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON access_grants
+    USING (tenant_visible(tenant_id))
+    WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE access_audit_log ENABLE ROW LEVEL SECURITY;
+ALTER TABLE access_audit_log FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON access_audit_log
+    USING (tenant_visible(tenant_id))
+    WITH CHECK (tenant_visible(tenant_id));
+
+-- Comments
+COMMENT ON FUNCTION tenant_visible(VARCHAR) IS 'Whether the current transaction may see rows of the given tenant';
@@ -0,0 +1,65 @@
+-- Migration: Add usage metering
+-- Version: 017
+-- Description: Requests per tenant, caller and endpoint, by day and by month,
+-- rolled up from the counters kept in Redis, and each tenant's monthly
+-- scoring quota.
+
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS monthly_score_quota INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS usage_daily (
+    tenant_id VARCHAR(255) NOT NULL,
+    day DATE NOT NULL,
+    caller_id VARCHAR(255) NOT NULL,
+    endpoint VARCHAR(255) NOT NULL,
+    billable BOOLEAN NOT NULL DEFAULT FALSE,
+    request_count BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, day, caller_id, endpoint)
+);
+
+CREATE TABLE IF NOT EXISTS usage_monthly (
+    tenant_id VARCHAR(255) NOT NULL,
+    month DATE NOT NULL,
+    caller_id VARCHAR(255) NOT NULL,
+    endpoint VARCHAR(255) NOT NULL,
+    billable BOOLEAN NOT NULL DEFAULT FALSE,
+    request_count BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, month, caller_id, endpoint)
+);
+
+-- Row-level security, as for the tables in migration 016
+ALTER TABLE usage_daily ENABLE ROW LEVEL SECURITY;
+ALTER TABLE usage_daily FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON usage_daily
+    USING (tenant_visible(tenant_id))
+    WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE usage_monthly ENABLE ROW LEVEL SECURITY;
+ALTER TABLE usage_monthly FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON usage_monthly
+    USING (tenant_visible(tenant_id))
+    WITH CHECK (tenant_visible(tenant_id));
+
+-- Triggers
+CREATE TRIGGER update_usage_daily_updated_at
+    BEFORE UPDATE ON usage_daily
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_usage_monthly_updated_at
+    BEFORE UPDATE ON usage_monthly
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Comments
+COMMENT ON COLUMN tenants.monthly_score_quota IS 'Billable scoring requests the tenant may make per calendar month (UTC); 0 for no limit';
+COMMENT ON TABLE usage_daily IS 'Successful requests per tenant, caller and endpoint per UTC day';
+COMMENT ON TABLE usage_monthly IS 'Successful requests per tenant, caller and endpoint per UTC month';
+COMMENT ON COLUMN usage_daily.caller_id IS 'User, client or API key ID that made the requests';
+COMMENT ON COLUMN usage_daily.endpoint IS 'Route as "METHOD /path", or the gRPC method name';
+COMMENT ON COLUMN usage_daily.billable IS 'Whether the endpoint counts towards the monthly scoring quota';
+COMMENT ON COLUMN usage_monthly.month IS 'First day of the month';
+COMMENT ON COLUMN api_keys.scopes IS 'What the key may do: score:write, score:read, history:read, usage:read';
@@ -0,0 +1,7 @@
+-- Migration: Stop keeping webhook receivers' responses
+-- Version: 018
+-- Description: Response bodies were returned with delivery attempts, which let
+-- a subscriber read back whatever its URL answered. Only the status code is
+-- kept now; the stored bodies are dropped.
+
+ALTER TABLE webhook_delivery_attempts DROP COLUMN IF EXISTS response_body;
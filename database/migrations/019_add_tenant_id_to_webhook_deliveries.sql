@@ -0,0 +1,42 @@
+-- Migration: Add tenants to webhook deliveries
+-- Version: 019
+-- Description: Webhook deliveries (notifications) and their attempts belong
+-- to their subscription's tenant. Existing deliveries and attempts take it
+-- from their subscription; other notifications move to the 'default' tenant.
+
+-- The subscriptions are behind row-level security (migration 016)
+SET app.all_tenants = 'on';
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(255);
+ALTER TABLE webhook_delivery_attempts ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(255);
+
+UPDATE notifications n
+SET tenant_id = s.tenant_id
+FROM webhook_subscriptions s
+WHERE s.id = n.webhook_subscription_id;
+
+UPDATE notifications SET tenant_id = 'default' WHERE tenant_id IS NULL;
+
+UPDATE webhook_delivery_attempts a
+SET tenant_id = n.tenant_id
+FROM notifications n
+WHERE n.id = a.notification_id;
+
+ALTER TABLE notifications ALTER COLUMN tenant_id SET NOT NULL;
+ALTER TABLE webhook_delivery_attempts ALTER COLUMN tenant_id SET NOT NULL;
+
+RESET app.all_tenants;
+
+-- Indexes
+CREATE INDEX idx_notifications_tenant_webhook_subscription
+    ON notifications(tenant_id, webhook_subscription_id, created_at DESC);
+CREATE INDEX idx_webhook_delivery_attempts_tenant_notification_id
+    ON webhook_delivery_attempts(tenant_id, notification_id, attempt);
+
+-- Superseded by the tenant-scoped indexes above
+DROP INDEX IF EXISTS idx_notifications_webhook_subscription;
+DROP INDEX IF EXISTS idx_webhook_delivery_attempts_notification_id;
+
+-- Comments
+COMMENT ON COLUMN notifications.tenant_id IS 'Tenant the notification belongs to; for webhook deliveries, the subscription''s';
+COMMENT ON COLUMN webhook_delivery_attempts.tenant_id IS 'Tenant of the delivery the attempt was made for';
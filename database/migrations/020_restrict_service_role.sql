@@ -0,0 +1,48 @@
+-- Migration: Extend row-level security and add the service's role
+-- Version: 020
+-- Description: Webhook deliveries, their attempts and API keys get the tenant
+-- isolation policies of migration 016. The service connects as
+-- credit_scoring_app, which owns nothing and is neither a superuser nor
+-- BYPASSRLS, so the policies always apply to it. The role is created without
+-- LOGIN; give it a password, or grant it to the service's login role, when
+-- deploying.
+
+ALTER TABLE notifications ENABLE ROW LEVEL SECURITY;
+ALTER TABLE notifications FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON notifications
+    USING (tenant_visible(tenant_id))
+    WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE webhook_delivery_attempts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_delivery_attempts FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON webhook_delivery_attempts
+    USING (tenant_visible(tenant_id))
+    WITH CHECK (tenant_visible(tenant_id));
+
+ALTER TABLE api_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON api_keys
+    USING (tenant_visible(tenant_id))
+    WITH CHECK (tenant_visible(tenant_id));
+
+-- Service role
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'credit_scoring_app') THEN
+        CREATE ROLE credit_scoring_app NOLOGIN NOSUPERUSER NOBYPASSRLS NOCREATEDB NOCREATEROLE;
+    END IF;
+END
+$$;
+
+GRANT USAGE ON SCHEMA public TO credit_scoring_app;
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO credit_scoring_app;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO credit_scoring_app;
+
+-- Tables added by later migrations, run by the same role as this one
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+    GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO credit_scoring_app;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+    GRANT USAGE, SELECT ON SEQUENCES TO credit_scoring_app;
+
+-- Comments
+COMMENT ON ROLE credit_scoring_app IS 'Role the credit scoring service connects as; subject to row-level security';
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/config"
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/model"
+	"credit-scoring/internal/repository"
+	"credit-scoring/internal/service"
+	"credit-scoring/internal/tenant"
+	"credit-scoring/pkg/database"
+	"credit-scoring/pkg/export"
+	"credit-scoring/pkg/kafka"
+)
+
+// runCommand executes an operator subcommand, e.g.
+//
+//	credit-scoring dlq inspect -topic credit-scoring-rescore-dlq -limit 20
+//	credit-scoring dlq replay -topic credit-scoring-events-dlq
+//	credit-scoring export -format parquet
+//	credit-scoring user create -email ops@example.com -role analyst < password.txt
+//	credit-scoring client create -name loan-origination -role service -tenant acme
+//	credit-scoring tenant create -id acme -name "Acme Lending"
+//	credit-scoring tenant set-model -id acme < model.json
+func runCommand(cfg *config.Config, log *zap.Logger, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	switch args[0] {
+	case "dlq":
+		return runDLQCommand(ctx, cfg, args[1:])
+	case "export":
+		return runExportCommand(ctx, cfg, log, args[1:])
+	case "user":
+		return runUserCommand(ctx, cfg, log, args[1:])
+	case "client":
+		return runClientCommand(ctx, cfg, log, args[1:])
+	case "tenant":
+		return runTenantCommand(ctx, cfg, log, args[1:])
+	default:
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+}
+
+func runDLQCommand(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: dlq inspect|replay [flags]")
+	}
+
+	fs := flag.NewFlagSet("dlq "+args[0], flag.ContinueOnError)
+	topic := fs.String("topic", cfg.KafkaRescoreDLQTopic, "dead-letter topic")
+	limit := fs.Int("limit", 0, "maximum number of messages (0 for all)")
+	idle := fs.Duration("idle", 10*time.Second, "replay: stop after no messages arrive for this long")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "inspect":
+		letters, err := kafka.InspectDeadLetters(ctx, cfg.KafkaBrokers, *topic, *limit)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(os.Stdout)
+		for _, dl := range letters {
+			if err := enc.Encode(dl); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "replay":
+		producer, err := kafka.NewProducer(cfg.KafkaBrokers)
+		if err != nil {
+			return err
+		}
+		defer producer.Close()
+		if err := useTopicEncodings(producer, cfg); err != nil {
+			return err
+		}
+
+		replayer := kafka.NewReplayer(cfg.KafkaBrokers, *topic, cfg.KafkaConsumerGroup+"-dlq-replay", producer)
+		defer replayer.Close()
+
+		replayed, err := replayer.Replay(ctx, *limit, *idle, func(dl kafka.DeadLetter) string {
+			switch dl.Stage {
+			case kafka.StagePublish:
+				return dl.OriginalTopic
+			case kafka.StageConsume:
+				// Only this service consumes the replay topic, so other
+				// consumers of the upstream topic don't see duplicates.
+				return cfg.KafkaRescoreReplayTopic
+			default:
+				fmt.Fprintf(os.Stderr, "skipping partition %d offset %d: unknown stage %q\n", dl.Partition, dl.Offset, dl.Stage)
+				return ""
+			}
+		})
+		fmt.Printf("replayed %d message(s) from %s\n", replayed, *topic)
+		return err
+
+	default:
+		return fmt.Errorf("unknown dlq command: %s", args[0])
+	}
+}
+
+// runExportCommand writes a credit_scores extract. By default it is
+// incremental, so a daily cron job exports each row once.
+func runExportCommand(ctx context.Context, cfg *config.Config, log *zap.Logger, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", export.FormatParquet, "file format: csv or parquet")
+	from := fs.String("from", "", "earliest created_at date to export (YYYY-MM-DD)")
+	to := fs.String("to", "", "latest created_at date to export (YYYY-MM-DD), inclusive")
+	incremental := fs.Bool("incremental", true, "only export rows after the job's watermark, then advance it")
+	job := fs.String("job", dto.DefaultExportJob, "export job whose watermark to use")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	req := &dto.ExportRequest{
+		Format:      *format,
+		From:        *from,
+		To:          *to,
+		Incremental: *incremental,
+		Job:         *job,
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return err
+	}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	db, err := database.NewPostgresDB(cfg.DatabaseURL, 2)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	exportService, err := newExportService(cfg, db, log)
+	if err != nil {
+		return err
+	}
+
+	result, err := exportService.Export(ctx, req)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(os.Stdout).Encode(result)
+}
+
+// runUserCommand creates a user who can log in. The password is read from
+// the first line of stdin so it stays out of the shell history.
+func runUserCommand(ctx context.Context, cfg *config.Config, log *zap.Logger, args []string) error {
+	if len(args) == 0 || args[0] != "create" {
+		return fmt.Errorf("usage: user create -email <email> -role <role> [-id <id>] [-tenant <tenant>] < password")
+	}
+
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	userID := fs.String("id", "", "user ID; generated if empty. Use the customer's ID for customer logins")
+	email := fs.String("email", "", "login email")
+	role := fs.String("role", "", "role: customer, loan_officer, analyst, admin or service")
+	tenantID := fs.String("tenant", tenant.Default, "tenant the user's tokens act for")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *email == "" {
+		return fmt.Errorf("-email is required")
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return fmt.Errorf("password must be given on stdin")
+	}
+	password := scanner.Text()
+	if len(password) < 12 || len(password) > 72 {
+		return fmt.Errorf("password must be 12 to 72 bytes long")
+	}
+
+	authService, closeDB, err := newAuthCommandService(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	user, err := authService.CreateUser(ctx, *userID, *tenantID, *email, password, *role)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created user %s (%s) in tenant %s\n", user.ID, user.Role, user.TenantID)
+	return nil
+}
+
+// runClientCommand creates an API client and prints its secret, which
+// can't be shown again.
+func runClientCommand(ctx context.Context, cfg *config.Config, log *zap.Logger, args []string) error {
+	if len(args) == 0 || args[0] != "create" {
+		return fmt.Errorf("usage: client create -name <name> [-role <role>] [-tenant <tenant>]")
+	}
+
+	fs := flag.NewFlagSet("client create", flag.ContinueOnError)
+	name := fs.String("name", "", "client name")
+	role := fs.String("role", "service", "role granted to the client's tokens")
+	tenantID := fs.String("tenant", tenant.Default, "tenant the client's tokens act for")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *name == "" {
+		return fmt.Errorf("-name is required")
+	}
+
+	authService, closeDB, err := newAuthCommandService(cfg, log)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	client, secret, err := authService.CreateClient(ctx, *tenantID, *name, *role)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(os.Stdout).Encode(map[string]string{
+		"clientId":     client.ID,
+		"clientSecret": secret,
+		"role":         client.Role,
+		"tenantId":     client.TenantID,
+	})
+}
+
+// runTenantCommand adds a tenant, sets its scoring model or sets its monthly
+// scoring quota. set-model reads the model as JSON from stdin; -builtin
+// restores the built-in model.
+func runTenantCommand(ctx context.Context, cfg *config.Config, log *zap.Logger, args []string) error {
+	if len(args) == 0 || (args[0] != "create" && args[0] != "set-model" && args[0] != "set-quota") {
+		return fmt.Errorf("usage: tenant create -id <id> -name <name> | tenant set-model -id <id> [-builtin] < model.json | tenant set-quota -id <id> -monthly <n>")
+	}
+
+	fs := flag.NewFlagSet("tenant "+args[0], flag.ContinueOnError)
+	tenantID := fs.String("id", "", "tenant ID, as used in tokens and API keys")
+	name := fs.String("name", "", "create: display name")
+	builtIn := fs.Bool("builtin", false, "set-model: restore the built-in scoring model")
+	monthly := fs.Int("monthly", -1, "set-quota: billable scoring requests per month, 0 for no limit")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *tenantID == "" {
+		return fmt.Errorf("-id is required")
+	}
+
+	db, err := database.NewPostgresDB(cfg.DatabaseURL, 2)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Replicas cache tenant settings briefly, so no Redis is needed here
+	tenantService := service.NewTenantService(repository.NewTenantRepository(db), nil, log)
+
+	if args[0] == "create" {
+		if *name == "" {
+			return fmt.Errorf("-name is required")
+		}
+		t, err := tenantService.CreateTenant(ctx, *tenantID, *name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("created tenant %s\n", t.ID)
+		return nil
+	}
+
+	if args[0] == "set-quota" {
+		if *monthly < 0 {
+			return fmt.Errorf("-monthly is required")
+		}
+		if err := tenantService.SetMonthlyScoreQuota(ctx, *tenantID, *monthly); err != nil {
+			return err
+		}
+		fmt.Printf("updated monthly score quota of tenant %s\n", *tenantID)
+		return nil
+	}
+
+	var scoringModel *model.ScoringModel
+	if !*builtIn {
+		scoringModel = &model.ScoringModel{}
+		dec := json.NewDecoder(os.Stdin)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(scoringModel); err != nil {
+			return fmt.Errorf("failed to read scoring model from stdin: %w", err)
+		}
+	}
+	if err := tenantService.SetScoringModel(ctx, *tenantID, scoringModel); err != nil {
+		return err
+	}
+	fmt.Printf("updated scoring model of tenant %s\n", *tenantID)
+	return nil
+}
+
+func newAuthCommandService(cfg *config.Config, log *zap.Logger) (*service.AuthService, func() error, error) {
+	db, err := database.NewPostgresDB(cfg.DatabaseURL, 2)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Creating users and clients never revokes tokens, so no Redis
+	authService, err := service.NewAuthService(repository.NewAuthRepository(db), nil, tokenConfig(cfg), log)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return authService, db.Close, nil
+}
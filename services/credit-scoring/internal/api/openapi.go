@@ -0,0 +1,268 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"credit-scoring/internal/dto"
+	"credit-scoring/pkg/errors"
+)
+
+// spec is the OpenAPI 3 description of the public API. It is maintained by
+// hand; Verify, run by the service's tests, checks it against the router and
+// DTOs.
+//
+//go:embed openapi.json
+var spec []byte
+
+// schemaTypes maps component schemas to the Go types they describe
+var schemaTypes = map[string]any{
+	"LoginRequest":             dto.LoginRequest{},
+	"ClientTokenRequest":       dto.ClientTokenRequest{},
+	"RefreshTokenRequest":      dto.RefreshTokenRequest{},
+	"TokenResponse":            dto.TokenResponse{},
+	"CalculateScoreRequest":    dto.CalculateScoreRequest{},
+	"LoanHistoryItem":          dto.LoanHistoryItem{},
+	"CreditScore":              dto.CreditScore{},
+	"CreditScoreHistory":       dto.CreditScoreHistory{},
+	"CreditScoreV2":            dto.CreditScoreV2{},
+	"SubScore":                 dto.SubScore{},
+	"ReasonCode":               dto.ReasonCode{},
+	"CreditScoreHistoryV2":     dto.CreditScoreHistoryV2{},
+	"CreditScoreTrend":         dto.CreditScoreTrend{},
+	"TrendBucket":              dto.TrendBucket{},
+	"GradeTransition":          dto.GradeTransition{},
+	"FactorChange":             dto.FactorChange{},
+	"CreateWebhookRequest":     dto.CreateWebhookRequest{},
+	"WebhookSubscription":      dto.WebhookSubscription{},
+	"WebhookDelivery":          dto.WebhookDelivery{},
+	"WebhookAttempt":           dto.WebhookAttempt{},
+	"WebhookEvent":             dto.WebhookEvent{},
+	"CreateAccessGrantRequest": dto.CreateAccessGrantRequest{},
+	"AccessGrant":              dto.AccessGrant{},
+	"CreateAPIKeyRequest":      dto.CreateAPIKeyRequest{},
+	"APIKey":                   dto.APIKey{},
+	"ExportRequest":            dto.ExportRequest{},
+	"ExportResult":             dto.ExportResult{},
+	"UsageReport":              dto.UsageReport{},
+	"UsageQuota":               dto.UsageQuota{},
+	"UsageEntry":               dto.UsageEntry{},
+	"SuccessResponse":          dto.SuccessResponse{},
+	"Problem":                  errors.Problem{},
+	"FieldError":               errors.FieldError{},
+}
+
+// queryTypes maps operations to the structs their query strings bind to
+var queryTypes = map[string]any{
+	"GET /api/v1/credit/history/{userId}": dto.HistoryQuery{},
+	"GET /api/v1/credit/trend/{userId}":   dto.TrendQuery{},
+	"GET /api/v1/credit/report/{userId}":  dto.ReportQuery{},
+	"GET /api/v2/credit/history/{userId}": dto.HistoryQuery{},
+	"GET /api/v1/admin/api-keys":          dto.APIKeyQuery{},
+	"GET /api/v1/usage":                   dto.UsageQuery{},
+}
+
+// Register serves the spec at /openapi.json and a Swagger UI at /docs.
+func Register(router gin.IRouter) {
+	router.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", spec)
+	})
+	router.GET("/docs", func(c *gin.Context) {
+		c.Header("Content-Security-Policy", docsPolicy)
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+	})
+}
+
+// swaggerUI is where the docs page loads Swagger UI from. The version is
+// pinned so the page only changes when this does.
+const swaggerUI = "https://unpkg.com/swagger-ui-dist@5.17.14/"
+
+// docsPolicy confines the docs page to the pinned Swagger UI and this service.
+const docsPolicy = "default-src 'none'; " +
+	"script-src " + swaggerUI + " 'sha256-" + docsScriptHash + "'; " +
+	"style-src " + swaggerUI + " 'unsafe-inline'; " +
+	"img-src 'self' data:; connect-src 'self'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+// docsScriptHash is the SHA-256 of docsPage's inline script, which is the
+// only inline script the policy allows.
+const docsScriptHash = "5oJmZexPFrjIq2pl1ffOlWPVFjMQufktfB/nd3RFuIc="
+
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Credit Scoring API</title>
+  <link rel="stylesheet" href="` + swaggerUI + `swagger-ui.css" crossorigin="anonymous" referrerpolicy="no-referrer">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="` + swaggerUI + `swagger-ui-bundle.js" crossorigin="anonymous" referrerpolicy="no-referrer"></script>
+  <script>` + docsScript + `</script>
+</body>
+</html>
+`
+
+const docsScript = `window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });`
+
+type document struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas    map[string]schema    `json:"schemas"`
+		Parameters map[string]parameter `json:"parameters"`
+	} `json:"components"`
+}
+
+type operation struct {
+	Parameters []parameter `json:"parameters"`
+}
+
+type parameter struct {
+	Ref  string `json:"$ref"`
+	Name string `json:"name"`
+	In   string `json:"in"`
+}
+
+type schema struct {
+	Properties map[string]json.RawMessage `json:"properties"`
+}
+
+var methods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "patch": true, "head": true, "options": true,
+}
+
+// Verify reports every difference between the spec and the running service:
+// /api routes missing from the spec or vice versa, and DTO fields or query
+// parameters the spec doesn't describe.
+func Verify(routes gin.RoutesInfo) error {
+	var doc document
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return fmt.Errorf("failed to parse openapi spec: %w", err)
+	}
+
+	var problems []string
+
+	// Routes
+	documented := map[string]operation{}
+	for path, item := range doc.Paths {
+		for method, raw := range item {
+			if !methods[method] {
+				continue
+			}
+			var op operation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return fmt.Errorf("failed to parse %s %s: %w", method, path, err)
+			}
+			documented[strings.ToUpper(method)+" "+path] = op
+		}
+	}
+
+	registered := map[string]bool{}
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, "/api/") {
+			continue
+		}
+		key := route.Method + " " + openAPIPath(route.Path)
+		registered[key] = true
+		if _, ok := documented[key]; !ok {
+			problems = append(problems, fmt.Sprintf("route %s is not in the spec", key))
+		}
+	}
+	for key := range documented {
+		if !registered[key] {
+			problems = append(problems, fmt.Sprintf("spec operation %s has no route", key))
+		}
+	}
+
+	// Query parameters
+	for key, v := range queryTypes {
+		op, ok := documented[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("spec operation %s is missing", key))
+			continue
+		}
+		params := map[string]bool{}
+		for _, p := range op.Parameters {
+			if p.Ref != "" {
+				p = doc.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+			}
+			if p.In == "query" {
+				params[p.Name] = true
+			}
+		}
+		problems = append(problems, compareFields(key+" query", fieldNames(v, "form"), params)...)
+	}
+
+	// Schemas
+	for name, v := range schemaTypes {
+		s, ok := doc.Components.Schemas[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("schema %s is missing", name))
+			continue
+		}
+		properties := map[string]bool{}
+		for p := range s.Properties {
+			properties[p] = true
+		}
+		problems = append(problems, compareFields("schema "+name, fieldNames(v, "json"), properties)...)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("openapi spec is out of date:\n  %s", strings.Join(problems, "\n  "))
+}
+
+// openAPIPath turns gin's /score/:userId into /score/{userId}
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// fieldNames returns the names a struct's exported fields use under tag
+func fieldNames(v any, tag string) map[string]bool {
+	names := map[string]bool{}
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+func compareFields(what string, fields, documented map[string]bool) []string {
+	var problems []string
+	for f := range fields {
+		if !documented[f] {
+			problems = append(problems, fmt.Sprintf("%s: %s is not documented", what, f))
+		}
+	}
+	for f := range documented {
+		if !fields[f] {
+			problems = append(problems, fmt.Sprintf("%s: %s does not exist", what, f))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,14 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+)
+
+func TestDocsPolicyAllowsDocsScript(t *testing.T) {
+	sum := sha256.Sum256([]byte(docsScript))
+	if got := base64.StdEncoding.EncodeToString(sum[:]); got != docsScriptHash {
+		t.Errorf("docsScriptHash = %s, want %s", docsScriptHash, got)
+	}
+}
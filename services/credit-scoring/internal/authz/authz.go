@@ -0,0 +1,208 @@
+// Package authz decides what an authenticated caller may do. Roles come from
+// the token's "role" claim and map to permissions; routes declare the
+// permission they need. Partner API keys have the partner role and are
+// further limited to their scopes. Whose data a caller may act on is decided
+// separately: staff may act on any user, partners on their tenant's users,
+// everyone else on themselves and on users who have delegated access to them.
+package authz
+
+import (
+	"context"
+
+	"credit-scoring/pkg/errors"
+)
+
+// Roles
+const (
+	RoleCustomer    = "customer"
+	RoleLoanOfficer = "loan_officer"
+	RoleAnalyst     = "analyst"
+	RoleAdmin       = "admin"
+	RoleService     = "service"
+	// RolePartner is held by partner API keys, never by tokens
+	RolePartner = "partner"
+)
+
+// Permission is an action on a kind of resource.
+type Permission string
+
+const (
+	ScoreCalculate Permission = "score:calculate"
+	ScoreRead      Permission = "score:read"
+	HistoryRead    Permission = "history:read"
+	ScoreRefresh   Permission = "score:refresh"
+	ReportRead     Permission = "report:read"
+	WebhookManage  Permission = "webhook:manage"
+	ExportCreate   Permission = "export:create"
+	GrantManage    Permission = "grant:manage"
+	APIKeyManage   Permission = "apikey:manage"
+	UsageRead      Permission = "usage:read"
+)
+
+var rolePermissions = map[string]map[Permission]bool{
+	// Customers see their own data and data delegated to them
+	RoleCustomer: {
+		ScoreRead:    true,
+		HistoryRead:  true,
+		ScoreRefresh: true,
+		ReportRead:   true,
+		GrantManage:  true,
+	},
+	RoleLoanOfficer: {
+		ScoreCalculate: true,
+		ScoreRead:      true,
+		HistoryRead:    true,
+		ScoreRefresh:   true,
+		ReportRead:     true,
+	},
+	RoleAnalyst: {
+		ScoreRead:   true,
+		HistoryRead: true,
+		ReportRead:  true,
+	},
+	// Backend integrations, e.g. loan origination
+	RoleService: {
+		ScoreCalculate: true,
+		ScoreRead:      true,
+		HistoryRead:    true,
+		ScoreRefresh:   true,
+		WebhookManage:  true,
+	},
+	// Partners get the permissions of their key's scopes
+	RolePartner: {
+		ScoreCalculate: true,
+		ScoreRead:      true,
+		HistoryRead:    true,
+		ScoreRefresh:   true,
+		UsageRead:      true,
+	},
+	RoleAdmin: {
+		ScoreCalculate: true,
+		ScoreRead:      true,
+		HistoryRead:    true,
+		ScoreRefresh:   true,
+		ReportRead:     true,
+		WebhookManage:  true,
+		ExportCreate:   true,
+		APIKeyManage:   true,
+		UsageRead:      true,
+	},
+}
+
+// Scope is what a partner API key may do. Each scope grants a set of
+// permissions.
+type Scope string
+
+const (
+	ScopeScoreWrite  Scope = "score:write"
+	ScopeScoreRead   Scope = "score:read"
+	ScopeHistoryRead Scope = "history:read"
+	ScopeUsageRead   Scope = "usage:read"
+)
+
+var scopePermissions = map[Scope][]Permission{
+	ScopeScoreWrite:  {ScoreCalculate, ScoreRefresh},
+	ScopeScoreRead:   {ScoreRead},
+	ScopeHistoryRead: {HistoryRead},
+	ScopeUsageRead:   {UsageRead},
+}
+
+// KnownScope reports whether scope is one of the scopes above.
+func KnownScope(scope string) bool {
+	_, ok := scopePermissions[Scope(scope)]
+	return ok
+}
+
+// staffRoles may act on any user's data. Everyone else needs to be the user
+// or to hold a delegation grant from them.
+var staffRoles = map[string]bool{
+	RoleLoanOfficer: true,
+	RoleAnalyst:     true,
+	RoleService:     true,
+	RoleAdmin:       true,
+}
+
+// KnownRole reports whether role is one of the roles tokens may carry.
+func KnownRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok && role != RolePartner
+}
+
+// Delegable are the permissions a user may delegate to another user.
+var Delegable = map[Permission]bool{
+	ScoreRead:    true,
+	HistoryRead:  true,
+	ScoreRefresh: true,
+	ReportRead:   true,
+}
+
+// Basis is why a caller may act on a user's data.
+type Basis string
+
+const (
+	BasisSelf       Basis = "self"
+	BasisStaff      Basis = "staff"
+	BasisDelegation Basis = "delegation"
+	BasisTenant     Basis = "tenant"
+)
+
+// Principal is the authenticated caller. For a partner API key UserID is the
+// key's ID.
+type Principal struct {
+	UserID string
+	Role   string
+	// Scopes limit a partner key's permissions
+	Scopes []Scope
+	// TenantID is the tenant the caller acts for: a partner key's tenant, or
+	// the token's tenantId claim
+	TenantID string
+	// RateLimit is a partner key's quota in requests per minute; zero for
+	// other callers
+	RateLimit int
+}
+
+// Can reports whether the caller's role grants perm and, for partners, one
+// of the key's scopes does too. Unknown roles have no permissions.
+func (p Principal) Can(perm Permission) bool {
+	if !rolePermissions[p.Role][perm] {
+		return false
+	}
+	if p.Role != RolePartner {
+		return true
+	}
+	for _, scope := range p.Scopes {
+		for _, granted := range scopePermissions[scope] {
+			if granted == perm {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsStaff reports whether the caller may act on any user's data.
+func (p Principal) IsStaff() bool {
+	return staffRoles[p.Role]
+}
+
+// Authorize returns a Forbidden error unless the caller's role grants perm.
+func Authorize(p Principal, perm Permission) error {
+	if !p.Can(perm) {
+		return errors.Forbidden("Insufficient permissions")
+	}
+	return nil
+}
+
+type principalKey struct{}
+
+// NewContext returns a context carrying p.
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the caller stored by NewContext. Without one it returns
+// the zero Principal, which has no permissions.
+func FromContext(ctx context.Context) Principal {
+	p, _ := ctx.Value(principalKey{}).(Principal)
+	return p
+}
@@ -4,11 +4,24 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	// Server
-	Port string
+	Port     string
+	GRPCPort string
+
+	// TLS for both servers; plaintext without TLSCertFile. With
+	// TLSClientCAFile, client certificates are verified, and those whose
+	// SPIFFE ID or DNS SAN is in MTLSIdentities authenticate as its role.
+	TLSCertFile          string
+	TLSKeyFile           string
+	TLSClientCAFile      string
+	TLSRequireClientCert bool
+	TLSReloadInterval    time.Duration
+	MTLSIdentities       map[string]string
 
 	// Database
 	DatabaseURL      string
@@ -19,12 +32,66 @@ type Config struct {
 	RedisPassword string
 
 	// Kafka
-	KafkaBrokers []string
+	KafkaBrokers        []string
+	KafkaTopicEncodings map[string]string
+	SchemaRegistryURL   string
+
+	// Kafka consumer, which re-scores users on upstream events when
+	// KafkaConsumerEnabled. KafkaRescoreTopics maps each topic consumed to
+	// the kind of event it carries.
+	KafkaConsumerEnabled     bool
+	KafkaConsumerGroup       string
+	KafkaRescoreTopics       map[string]string
+	KafkaRescoreDLQTopic     string
+	KafkaRescoreReplayTopic  string
+	KafkaPublishDLQTopic     string
+	KafkaConsumerMaxAttempts int
+
+	// JWT. HS256 tokens are verified with JWTSecret and asymmetrically
+	// signed ones with keys from JWTJWKSURL or JWTPublicKeyFile.
+	JWTSecret             string
+	JWTJWKSURL            string
+	JWTPublicKeyFile      string
+	JWTKeyRefreshInterval time.Duration
+	JWTIssuer             string
+	JWTAudience           string
+	JWTClockSkew          time.Duration
+	// Lifetimes of the tokens this service issues
+	JWTExpiry        time.Duration
+	JWTRefreshExpiry time.Duration
+
+	// Idempotency
+	IdempotencyKeyTTL time.Duration
+
+	// Rate limits, as "<rate>/<period>": RateLimitIP for all requests from
+	// an IP, and per caller RateLimitRoutes by "METHOD /path" and
+	// RateLimitDefault shared by all other routes
+	RateLimitIP      string
+	RateLimitDefault string
+	RateLimitRoutes  map[string]string
+
+	// Usage metering: how often counters are rolled up to Postgres
+	UsageRollupInterval time.Duration
 
-	// JWT
-	JWTSecret       string
-	JWTExpiry       string
-	JWTRefreshExpiry string
+	// API versioning: when v1 endpoints with a v2 successor were deprecated
+	// and when they will be removed
+	APIV1Deprecation time.Time
+	APIV1Sunset      time.Time
+
+	// Webhooks
+	WebhookMaxAttempts  int
+	WebhookPollInterval time.Duration
+	WebhookTimeout      time.Duration
+
+	// Warehouse exports
+	ExportDestination string
+	ExportDir         string
+	ExportS3Endpoint  string
+	ExportS3Region    string
+	ExportS3Bucket    string
+	ExportS3AccessKey string
+	ExportS3SecretKey string
+	ExportS3UseSSL    bool
 
 	// Tracing
 	JaegerEndpoint string
@@ -34,20 +101,68 @@ type Config struct {
 	CreditBureauAPIKey string
 }
 
+// defaultRescoreTopics are the upstream topics, named after the kind of event
+// they carry.
+var defaultRescoreTopics = map[string]string{
+	"loan-repaid":    "loan-repaid",
+	"loan-defaulted": "loan-defaulted",
+	"income-updated": "income-updated",
+}
+
 func Load() (*Config, error) {
 	cfg := &Config{
-		Port:                getEnv("PORT", "8001"),
-		DatabaseURL:         getEnv("DATABASE_URL", ""),
-		DatabaseMaxConns:    getEnvAsInt("DATABASE_MAX_CONNECTIONS", 50),
-		RedisURL:            getEnv("REDIS_URL", "localhost:6379"),
-		RedisPassword:       getEnv("REDIS_PASSWORD", ""),
-		KafkaBrokers:        []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
-		JWTSecret:           getEnv("JWT_SECRET", ""),
-		JWTExpiry:           getEnv("JWT_EXPIRY", "15m"),
-		JWTRefreshExpiry:    getEnv("JWT_REFRESH_EXPIRY", "7d"),
-		JaegerEndpoint:      getEnv("JAEGER_ENDPOINT", "http://localhost:14268/api/traces"),
-		CreditBureauAPIURL:  getEnv("CREDIT_BUREAU_API_URL", ""),
-		CreditBureauAPIKey:  getEnv("CREDIT_BUREAU_API_KEY", ""),
+		Port:                     getEnv("PORT", "8001"),
+		GRPCPort:                 getEnv("GRPC_PORT", "9001"),
+		TLSCertFile:              getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:               getEnv("TLS_KEY_FILE", ""),
+		TLSClientCAFile:          getEnv("TLS_CLIENT_CA_FILE", ""),
+		TLSRequireClientCert:     getEnvAsBool("TLS_REQUIRE_CLIENT_CERT", false),
+		TLSReloadInterval:        getEnvAsDuration("TLS_RELOAD_INTERVAL", 30*time.Second),
+		MTLSIdentities:           getEnvAsMap("MTLS_IDENTITIES"),
+		DatabaseURL:              getEnv("DATABASE_URL", ""),
+		DatabaseMaxConns:         getEnvAsInt("DATABASE_MAX_CONNECTIONS", 50),
+		RedisURL:                 getEnv("REDIS_URL", "localhost:6379"),
+		RedisPassword:            getEnv("REDIS_PASSWORD", ""),
+		KafkaBrokers:             []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
+		KafkaTopicEncodings:      getEnvAsMap("KAFKA_TOPIC_ENCODINGS"),
+		SchemaRegistryURL:        getEnv("SCHEMA_REGISTRY_URL", ""),
+		KafkaConsumerEnabled:     getEnvAsBool("KAFKA_CONSUMER_ENABLED", false),
+		KafkaConsumerGroup:       getEnv("KAFKA_CONSUMER_GROUP", "credit-scoring"),
+		KafkaRescoreTopics:       getEnvAsMapOrDefault("KAFKA_RESCORE_TOPICS", defaultRescoreTopics),
+		KafkaRescoreDLQTopic:     getEnv("KAFKA_RESCORE_DLQ_TOPIC", "credit-scoring-rescore-dlq"),
+		KafkaRescoreReplayTopic:  getEnv("KAFKA_RESCORE_REPLAY_TOPIC", "credit-scoring-rescore-replay"),
+		KafkaPublishDLQTopic:     getEnv("KAFKA_PUBLISH_DLQ_TOPIC", "credit-scoring-events-dlq"),
+		KafkaConsumerMaxAttempts: getEnvAsInt("KAFKA_CONSUMER_MAX_ATTEMPTS", 5),
+		JWTSecret:                getEnv("JWT_SECRET", ""),
+		JWTJWKSURL:               getEnv("JWT_JWKS_URL", ""),
+		JWTPublicKeyFile:         getEnv("JWT_PUBLIC_KEY_FILE", ""),
+		JWTKeyRefreshInterval:    getEnvAsDuration("JWT_KEY_REFRESH_INTERVAL", 10*time.Minute),
+		JWTIssuer:                getEnv("JWT_ISSUER", ""),
+		JWTAudience:              getEnv("JWT_AUDIENCE", ""),
+		JWTClockSkew:             getEnvAsDuration("JWT_CLOCK_SKEW", 30*time.Second),
+		JWTExpiry:                getEnvAsDuration("JWT_EXPIRY", 15*time.Minute),
+		JWTRefreshExpiry:         getEnvAsDuration("JWT_REFRESH_EXPIRY", 7*24*time.Hour),
+		IdempotencyKeyTTL:        getEnvAsDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		RateLimitIP:              getEnv("RATE_LIMIT_IP", "200/s"),
+		RateLimitDefault:         getEnv("RATE_LIMIT_DEFAULT", "100/s"),
+		RateLimitRoutes:          getEnvAsMap("RATE_LIMIT_ROUTES"),
+		UsageRollupInterval:      getEnvAsDuration("USAGE_ROLLUP_INTERVAL", time.Minute),
+		APIV1Deprecation:         getEnvAsDate("API_V1_DEPRECATION_DATE", "2026-11-01"),
+		APIV1Sunset:              getEnvAsDate("API_V1_SUNSET_DATE", "2027-05-01"),
+		WebhookMaxAttempts:       getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 10),
+		WebhookPollInterval:      getEnvAsDuration("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+		WebhookTimeout:           getEnvAsDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		ExportDestination:        getEnv("EXPORT_DESTINATION", "local"),
+		ExportDir:                getEnv("EXPORT_DIR", "./exports"),
+		ExportS3Endpoint:         getEnv("EXPORT_S3_ENDPOINT", ""),
+		ExportS3Region:           getEnv("EXPORT_S3_REGION", "us-east-1"),
+		ExportS3Bucket:           getEnv("EXPORT_S3_BUCKET", ""),
+		ExportS3AccessKey:        getEnv("EXPORT_S3_ACCESS_KEY", ""),
+		ExportS3SecretKey:        getEnv("EXPORT_S3_SECRET_KEY", ""),
+		ExportS3UseSSL:           getEnvAsBool("EXPORT_S3_USE_SSL", true),
+		JaegerEndpoint:           getEnv("JAEGER_ENDPOINT", "http://localhost:14268/api/traces"),
+		CreditBureauAPIURL:       getEnv("CREDIT_BUREAU_API_URL", ""),
+		CreditBureauAPIKey:       getEnv("CREDIT_BUREAU_API_KEY", ""),
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -61,8 +176,40 @@ func (c *Config) Validate() error {
 	if c.DatabaseURL == "" {
 		return fmt.Errorf("DATABASE_URL is required")
 	}
-	if c.JWTSecret == "" {
-		return fmt.Errorf("JWT_SECRET is required")
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if c.TLSClientCAFile != "" && c.TLSCertFile == "" {
+		return fmt.Errorf("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE")
+	}
+	if (c.TLSRequireClientCert || len(c.MTLSIdentities) > 0) && c.TLSClientCAFile == "" {
+		return fmt.Errorf("TLS_REQUIRE_CLIENT_CERT and MTLS_IDENTITIES require TLS_CLIENT_CA_FILE")
+	}
+	if c.TLSReloadInterval <= 0 {
+		return fmt.Errorf("TLS_RELOAD_INTERVAL must be positive")
+	}
+	if c.JWTJWKSURL != "" && c.JWTPublicKeyFile != "" {
+		return fmt.Errorf("set only one of JWT_JWKS_URL and JWT_PUBLIC_KEY_FILE")
+	}
+	if c.JWTJWKSURL != "" || c.JWTPublicKeyFile != "" {
+		// Keys from an identity provider may sign tokens for other services
+		if c.JWTIssuer == "" || c.JWTAudience == "" {
+			return fmt.Errorf("JWT_ISSUER and JWT_AUDIENCE are required with JWT_JWKS_URL or JWT_PUBLIC_KEY_FILE")
+		}
+	} else if c.JWTSecret == "" {
+		return fmt.Errorf("JWT_SECRET, JWT_JWKS_URL or JWT_PUBLIC_KEY_FILE is required")
+	}
+	if c.JWTKeyRefreshInterval <= 0 {
+		return fmt.Errorf("JWT_KEY_REFRESH_INTERVAL must be positive")
+	}
+	if c.UsageRollupInterval <= 0 {
+		return fmt.Errorf("USAGE_ROLLUP_INTERVAL must be positive")
+	}
+	if c.JWTClockSkew < 0 {
+		return fmt.Errorf("JWT_CLOCK_SKEW must not be negative")
+	}
+	if c.JWTExpiry <= 0 || c.JWTRefreshExpiry <= c.JWTExpiry {
+		return fmt.Errorf("JWT_EXPIRY must be positive and shorter than JWT_REFRESH_EXPIRY")
 	}
 	return nil
 }
@@ -82,3 +229,61 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsDuration parses a Go duration, or a whole number of days like "7d".
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if days, ok := strings.CutSuffix(value, "d"); ok {
+			if n, err := strconv.Atoi(days); err == nil {
+				return time.Duration(n) * 24 * time.Hour
+			}
+		}
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsDate parses a YYYY-MM-DD date as midnight UTC. "none" disables it
+// and returns the zero time.
+func getEnvAsDate(key, defaultValue string) time.Time {
+	value := getEnv(key, defaultValue)
+	if value == "none" {
+		return time.Time{}
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t
+	}
+	t, _ := time.Parse("2006-01-02", defaultValue)
+	return t
+}
+
+// getEnvAsMapOrDefault is getEnvAsMap, or defaultValue if key is unset.
+func getEnvAsMapOrDefault(key string, defaultValue map[string]string) map[string]string {
+	if os.Getenv(key) == "" {
+		return defaultValue
+	}
+	return getEnvAsMap(key)
+}
+
+// getEnvAsMap parses a comma-separated list of key=value pairs.
+func getEnvAsMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && k != "" {
+			result[k] = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"credit-scoring/internal/authz"
+	"credit-scoring/pkg/errors"
+)
+
+type CreateAccessGrantRequest struct {
+	// GranteeID is the user being given access
+	GranteeID   string   `json:"granteeId" binding:"required,max=255"`
+	Permissions []string `json:"permissions" binding:"required,min=1"`
+	// ExpiresAt is optional; without it the grant lasts until revoked
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+func (r *CreateAccessGrantRequest) Validate(ownerID string) error {
+	var fields []errors.FieldError
+
+	if r.GranteeID == ownerID {
+		fields = append(fields, errors.FieldError{Field: "granteeId", Message: "must be another user"})
+	}
+
+	seen := map[string]bool{}
+	for _, p := range r.Permissions {
+		if !authz.Delegable[authz.Permission(p)] || seen[p] {
+			fields = append(fields, errors.FieldError{
+				Field:   "permissions",
+				Message: "must only contain distinct values of score:read, history:read, score:refresh, report:read",
+			})
+			break
+		}
+		seen[p] = true
+	}
+
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		fields = append(fields, errors.FieldError{Field: "expiresAt", Message: "must be in the future"})
+	}
+
+	if len(fields) > 0 {
+		return errors.Validation("Request validation failed", fields...)
+	}
+
+	return nil
+}
+
+type AccessGrant struct {
+	ID          string     `json:"id"`
+	OwnerID     string     `json:"ownerId"`
+	GranteeID   string     `json:"granteeId"`
+	Permissions []string   `json:"permissions"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	RevokedAt   *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
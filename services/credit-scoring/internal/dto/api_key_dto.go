@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"time"
+
+	"credit-scoring/internal/authz"
+	"credit-scoring/pkg/errors"
+)
+
+// DefaultAPIKeyRateLimit is the requests per minute a key may make unless it
+// is created with another limit
+const DefaultAPIKeyRateLimit = 600
+
+type CreateAPIKeyRequest struct {
+	Name     string   `json:"name" binding:"required,max=255"`
+	TenantID string   `json:"tenantId" binding:"required,max=255"`
+	Scopes   []string `json:"scopes" binding:"required,min=1"`
+	// RateLimit is requests per minute; zero means DefaultAPIKeyRateLimit
+	RateLimit int `json:"rateLimit" binding:"omitempty,min=1,max=100000"`
+	// ExpiresAt is optional; without it the key lasts until revoked
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+func (r *CreateAPIKeyRequest) Validate() error {
+	var fields []errors.FieldError
+
+	seen := map[string]bool{}
+	for _, s := range r.Scopes {
+		if !authz.KnownScope(s) || seen[s] {
+			fields = append(fields, errors.FieldError{
+				Field:   "scopes",
+				Message: "must only contain distinct values of score:write, score:read, history:read, usage:read",
+			})
+			break
+		}
+		seen[s] = true
+	}
+
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		fields = append(fields, errors.FieldError{Field: "expiresAt", Message: "must be in the future"})
+	}
+
+	if len(fields) > 0 {
+		return errors.Validation("Request validation failed", fields...)
+	}
+
+	return nil
+}
+
+type APIKey struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	TenantID string `json:"tenantId"`
+	// Key is only returned when the key is created
+	Key        string     `json:"key,omitempty"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	RateLimit  int        `json:"rateLimit"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedBy  string     `json:"createdBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// APIKeyQuery filters the key list
+type APIKeyQuery struct {
+	TenantID string `form:"tenantId" binding:"max=255"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+type LoginRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+	// bcrypt ignores anything past 72 bytes
+	Password string `json:"password" binding:"required,max=72"`
+}
+
+// ClientTokenRequest exchanges client credentials for an access token
+type ClientTokenRequest struct {
+	ClientID     string `json:"clientId" binding:"required,max=255"`
+	ClientSecret string `json:"clientSecret" binding:"required,max=255"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required,max=255"`
+}
+
+type TokenResponse struct {
+	AccessToken string `json:"accessToken"`
+	// RefreshToken is only issued to users; clients request a new token
+	RefreshToken string `json:"refreshToken,omitempty"`
+	TokenType    string `json:"tokenType"`
+	// ExpiresIn is the access token lifetime in seconds
+	ExpiresIn int `json:"expiresIn"`
+}
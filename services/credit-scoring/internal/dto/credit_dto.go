@@ -1,8 +1,11 @@
 package dto
 
 import (
-	"fmt"
+	"encoding/base64"
+	"encoding/json"
 	"time"
+
+	"credit-scoring/pkg/errors"
 )
 
 type CalculateScoreRequest struct {
@@ -15,6 +18,9 @@ type CalculateScoreRequest struct {
 }
 
 type LoanHistoryItem struct {
+	// LoanID identifies the loan in upstream loan events, so a repayment or
+	// default updates the loan instead of adding another
+	LoanID      string    `json:"loanId,omitempty"`
 	Amount      float64   `json:"amount"`
 	Status      string    `json:"status"`
 	PaymentDate time.Time `json:"paymentDate"`
@@ -22,37 +28,47 @@ type LoanHistoryItem struct {
 
 func (r *CalculateScoreRequest) Validate() error {
 	validStatuses := map[string]bool{
-		"employed":     true,
+		"employed":      true,
 		"self-employed": true,
-		"unemployed":   true,
-		"retired":      true,
+		"unemployed":    true,
+		"retired":       true,
 	}
 
+	var fields []errors.FieldError
+
 	if !validStatuses[r.EmploymentStatus] {
-		return fmt.Errorf("invalid employment status: %s", r.EmploymentStatus)
+		fields = append(fields, errors.FieldError{
+			Field:   "employmentStatus",
+			Message: "must be one of employed, self-employed, unemployed, retired",
+		})
 	}
 
 	if r.IncomeAmount < 0 {
-		return fmt.Errorf("income amount cannot be negative")
+		fields = append(fields, errors.FieldError{Field: "incomeAmount", Message: "cannot be negative"})
+	}
+
+	if len(fields) > 0 {
+		return errors.Validation("Request validation failed", fields...)
 	}
 
 	return nil
 }
 
 type CreditScore struct {
-	ID            string    `json:"id"`
-	UserID        string    `json:"userId"`
-	Score         int       `json:"score"`
-	Grade         string    `json:"grade"`
-	Factors       []string  `json:"factors"`
-	Recommendation string   `json:"recommendation"`
-	CalculatedAt  time.Time `json:"calculatedAt"`
-	ExpiresAt     time.Time `json:"expiresAt"`
+	ID             string    `json:"id"`
+	UserID         string    `json:"userId"`
+	Score          int       `json:"score"`
+	Grade          string    `json:"grade"`
+	Factors        []string  `json:"factors"`
+	Recommendation string    `json:"recommendation"`
+	CalculatedAt   time.Time `json:"calculatedAt"`
+	ExpiresAt      time.Time `json:"expiresAt"`
 }
 
 type CreditScoreHistory struct {
-	UserID  string        `json:"userId"`
-	History []CreditScore `json:"history"`
+	UserID     string        `json:"userId"`
+	History    []CreditScore `json:"history"`
+	NextCursor string        `json:"nextCursor,omitempty"`
 }
 
 type SuccessResponse struct {
@@ -60,3 +76,148 @@ type SuccessResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 	Message string      `json:"message,omitempty"`
 }
+
+type HistoryQuery struct {
+	Limit  int       `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string    `form:"cursor"`
+	From   time.Time `form:"from" time_format:"2006-01-02"`
+	To     time.Time `form:"to" time_format:"2006-01-02"`
+	Grade  string    `form:"grade"`
+}
+
+func (q *HistoryQuery) Validate() error {
+	var fields []errors.FieldError
+
+	if q.Grade != "" && !validGrades[q.Grade] {
+		fields = append(fields, errors.FieldError{
+			Field:   "grade",
+			Message: "must be one of Excellent, Very Good, Good, Fair, Poor",
+		})
+	}
+
+	if !q.From.IsZero() && !q.To.IsZero() && q.To.Before(q.From) {
+		fields = append(fields, errors.FieldError{Field: "to", Message: "must not be before from"})
+	}
+
+	if q.Cursor != "" {
+		if _, err := DecodeHistoryCursor(q.Cursor); err != nil {
+			fields = append(fields, errors.FieldError{Field: "cursor", Message: "is not a valid cursor"})
+		}
+	}
+
+	if len(fields) > 0 {
+		return errors.Validation("Query validation failed", fields...)
+	}
+
+	return nil
+}
+
+var validGrades = map[string]bool{
+	"Excellent": true,
+	"Very Good": true,
+	"Good":      true,
+	"Fair":      true,
+	"Poor":      true,
+}
+
+// HistoryCursor marks the last score of a history page. It is handed to
+// clients as an opaque string.
+type HistoryCursor struct {
+	CalculatedAt time.Time `json:"t"`
+	ID           string    `json:"id"`
+}
+
+func (c HistoryCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeHistoryCursor(s string) (*HistoryCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidCursor()
+	}
+
+	var c HistoryCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, errInvalidCursor()
+	}
+	return &c, nil
+}
+
+// errInvalidCursor returns a new error each time, since callers may modify it,
+// e.g. with WithCode
+func errInvalidCursor() error {
+	return errors.Validation("Query validation failed", errors.FieldError{
+		Field:   "cursor",
+		Message: "is not a valid cursor",
+	})
+}
+
+type TrendQuery struct {
+	Period string    `form:"period"`
+	From   time.Time `form:"from" time_format:"2006-01-02"`
+	To     time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+func (q *TrendQuery) Validate() error {
+	var fields []errors.FieldError
+
+	switch q.Period {
+	case "":
+		q.Period = "monthly"
+	case "monthly", "quarterly":
+	default:
+		fields = append(fields, errors.FieldError{Field: "period", Message: "must be monthly or quarterly"})
+	}
+
+	if !q.From.IsZero() && !q.To.IsZero() && q.To.Before(q.From) {
+		fields = append(fields, errors.FieldError{Field: "to", Message: "must not be before from"})
+	}
+
+	if len(fields) > 0 {
+		return errors.Validation("Query validation failed", fields...)
+	}
+
+	return nil
+}
+
+type CreditScoreTrend struct {
+	UserID           string            `json:"userId"`
+	Period           string            `json:"period"`
+	From             time.Time         `json:"from"`
+	To               time.Time         `json:"to"`
+	Buckets          []TrendBucket     `json:"buckets"`
+	GradeTransitions []GradeTransition `json:"gradeTransitions"`
+	FactorChanges    []FactorChange    `json:"factorChanges"`
+}
+
+// TrendBucket summarizes the scores calculated in one period. Periods without
+// scores are omitted.
+type TrendBucket struct {
+	Label       string    `json:"label"`
+	PeriodStart time.Time `json:"periodStart"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+	Count       int       `json:"count"`
+	Min         int       `json:"min"`
+	Max         int       `json:"max"`
+	Average     float64   `json:"average"`
+	Latest      int       `json:"latest"`
+	// Change in average score vs the previous period; nil for the first
+	// bucket and when the previous period had no scores
+	Change *float64 `json:"change"`
+}
+
+type GradeTransition struct {
+	ScoreID      string    `json:"scoreId"`
+	From         string    `json:"from"`
+	To           string    `json:"to"`
+	CalculatedAt time.Time `json:"calculatedAt"`
+}
+
+type FactorChange struct {
+	ScoreID      string    `json:"scoreId"`
+	CalculatedAt time.Time `json:"calculatedAt"`
+	Added        []string  `json:"added"`
+	Removed      []string  `json:"removed"`
+}
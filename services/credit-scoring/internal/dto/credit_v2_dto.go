@@ -0,0 +1,68 @@
+package dto
+
+import "time"
+
+// Sub-score names
+const (
+	SubScoreIncome      = "income"
+	SubScoreEmployment  = "employment"
+	SubScoreAccountAge  = "accountAge"
+	SubScoreLoanHistory = "loanHistory"
+)
+
+// Reason codes explain what is holding a score back
+const (
+	ReasonLowIncome           = "RC01"
+	ReasonEmploymentStatus    = "RC02"
+	ReasonShortAccountHistory = "RC03"
+	ReasonMissedPayments      = "RC04"
+	ReasonNoLoanHistory       = "RC05"
+)
+
+// ReasonDescriptions maps each reason code to a description safe to show to
+// the consumer.
+var ReasonDescriptions = map[string]string{
+	ReasonLowIncome:           "Income level limits the score",
+	ReasonEmploymentStatus:    "Current employment status limits the score",
+	ReasonShortAccountHistory: "Length of account history is short",
+	ReasonMissedPayments:      "Loans not repaid as agreed",
+	ReasonNoLoanHistory:       "No loan repayment history",
+}
+
+// CreditScoreV2 is the v2 representation of a score. Timestamps are RFC 3339
+// in UTC.
+type CreditScoreV2 struct {
+	ID     string `json:"id"`
+	UserID string `json:"userId"`
+	Score  int    `json:"score"`
+	Grade  string `json:"grade"`
+	// ModelVersion identifies the scoring model that produced the score
+	ModelVersion string `json:"modelVersion"`
+	// ProbabilityOfDefault is the estimated 12-month probability of default
+	ProbabilityOfDefault float64 `json:"probabilityOfDefault"`
+	// SubScores are empty for scores calculated before v2 was introduced
+	SubScores      []SubScore   `json:"subScores"`
+	ReasonCodes    []ReasonCode `json:"reasonCodes"`
+	Factors        []string     `json:"factors"`
+	Recommendation string       `json:"recommendation"`
+	CalculatedAt   time.Time    `json:"calculatedAt"`
+	ExpiresAt      time.Time    `json:"expiresAt"`
+}
+
+// SubScore is one weighted component of the score, on the same 300-850 scale.
+type SubScore struct {
+	Name   string  `json:"name"`
+	Score  int     `json:"score"`
+	Weight float64 `json:"weight"`
+}
+
+type ReasonCode struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+type CreditScoreHistoryV2 struct {
+	UserID     string          `json:"userId"`
+	History    []CreditScoreV2 `json:"history"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+}
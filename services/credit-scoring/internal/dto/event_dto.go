@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// UpstreamEvent is the payload of the loan and income events that trigger
+// a score recalculation. Income-updated events without incomeAmount leave the
+// user's income unchanged.
+type UpstreamEvent struct {
+	EventID string `json:"eventId"`
+	// TenantID is the user's tenant; events without one are for the
+	// default tenant
+	TenantID         string    `json:"tenantId,omitempty"`
+	UserID           string    `json:"userId"`
+	LoanID           string    `json:"loanId,omitempty"`
+	Amount           float64   `json:"amount,omitempty"`
+	IncomeAmount     *float64  `json:"incomeAmount,omitempty"`
+	EmploymentStatus string    `json:"employmentStatus,omitempty"`
+	OccurredAt       time.Time `json:"occurredAt"`
+}
@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"regexp"
+	"time"
+
+	"credit-scoring/pkg/errors"
+)
+
+// DefaultExportJob names the watermark used when a request doesn't give one.
+const DefaultExportJob = "credit_scores"
+
+// exportJobPattern keeps job names safe to use in file and object keys
+var exportJobPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+type ExportRequest struct {
+	Format string `json:"format" binding:"required,oneof=csv parquet"`
+	// From and To bound created_at as YYYY-MM-DD; To is inclusive
+	From string `json:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `json:"to" binding:"omitempty,datetime=2006-01-02"`
+	// Incremental exports only rows newer than the job's watermark and
+	// advances it on success
+	Incremental bool   `json:"incremental"`
+	Job         string `json:"job" binding:"omitempty,max=100"`
+}
+
+func (r *ExportRequest) Validate() error {
+	var fields []errors.FieldError
+
+	// Dates are already checked to be YYYY-MM-DD, so they compare as strings
+	if r.From != "" && r.To != "" && r.To < r.From {
+		fields = append(fields, errors.FieldError{Field: "to", Message: "must not be before from"})
+	}
+
+	if r.Job != "" && !exportJobPattern.MatchString(r.Job) {
+		fields = append(fields, errors.FieldError{Field: "job", Message: "must only contain a-z, 0-9, _ and -"})
+	}
+
+	if len(fields) > 0 {
+		return errors.Validation("Request validation failed", fields...)
+	}
+
+	return nil
+}
+
+type ExportResult struct {
+	Job         string `json:"job"`
+	Format      string `json:"format"`
+	Incremental bool   `json:"incremental"`
+	// Location is empty when no rows matched and nothing was written
+	Location string `json:"location,omitempty"`
+	Rows     int64  `json:"rows"`
+	// Watermark is the created_at of the last exported row of an
+	// incremental run
+	Watermark   *time.Time `json:"watermark,omitempty"`
+	StartedAt   time.Time  `json:"startedAt"`
+	CompletedAt time.Time  `json:"completedAt"`
+}
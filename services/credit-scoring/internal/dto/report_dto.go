@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"credit-scoring/pkg/errors"
+)
+
+// Report formats.
+const (
+	ReportFormatPDF  = "pdf"
+	ReportFormatHTML = "html"
+)
+
+type ReportQuery struct {
+	// Format defaults to pdf
+	Format string `form:"format"`
+}
+
+func (q *ReportQuery) Validate() error {
+	switch q.Format {
+	case "", ReportFormatPDF, ReportFormatHTML:
+	default:
+		return errors.Validation("Query validation failed", errors.FieldError{
+			Field:   "format",
+			Message: "must be one of pdf, html",
+		})
+	}
+	return nil
+}
+
+// CreditReport is everything shown in a downloadable credit report.
+type CreditReport struct {
+	UserID      string
+	GeneratedAt time.Time
+	Score       *CreditScore
+	// History is oldest first
+	History    []CreditScore
+	Tradelines []Tradeline
+	// TradelinesNote explains why tradelines are missing, if they are
+	TradelinesNote string
+}
+
+type Tradeline struct {
+	Creditor      string
+	AccountType   string
+	AccountNumber string
+	Status        string
+	Balance       float64
+	CreditLimit   float64
+	PaymentStatus string
+	OpenedAt      time.Time
+	ReportedAt    time.Time
+}
@@ -0,0 +1,86 @@
+package dto
+
+import (
+	"time"
+
+	"credit-scoring/pkg/errors"
+)
+
+// maxUsageRange is the longest period a usage report may cover
+const maxUsageRange = 366 * 24 * time.Hour
+
+// UsageQuery selects a usage report. From and To are inclusive days; they
+// default to the current month so far.
+type UsageQuery struct {
+	From        time.Time `form:"from" time_format:"2006-01-02"`
+	To          time.Time `form:"to" time_format:"2006-01-02"`
+	Granularity string    `form:"granularity"`
+	// TenantID lets admins report on another tenant than their own
+	TenantID string `form:"tenantId" binding:"max=255"`
+}
+
+func (q *UsageQuery) Validate() error {
+	var fields []errors.FieldError
+
+	switch q.Granularity {
+	case "":
+		q.Granularity = "day"
+	case "day", "month":
+	default:
+		fields = append(fields, errors.FieldError{Field: "granularity", Message: "must be day or month"})
+	}
+
+	if !q.From.IsZero() && !q.To.IsZero() {
+		if q.To.Before(q.From) {
+			fields = append(fields, errors.FieldError{Field: "to", Message: "must not be before from"})
+		} else if q.To.Sub(q.From) >= maxUsageRange {
+			fields = append(fields, errors.FieldError{Field: "to", Message: "must be within 366 days of from"})
+		}
+	}
+
+	if len(fields) > 0 {
+		return errors.Validation("Query validation failed", fields...)
+	}
+
+	return nil
+}
+
+// UsageReport is a tenant's requests per caller and endpoint. Usage is rolled
+// up from live counters periodically, so the latest requests may be missing;
+// Quota is always current.
+type UsageReport struct {
+	TenantID    string       `json:"tenantId"`
+	From        string       `json:"from"`
+	To          string       `json:"to"`
+	Granularity string       `json:"granularity"`
+	Quota       UsageQuota   `json:"quota"`
+	Totals      UsageTotals  `json:"totals"`
+	Usage       []UsageEntry `json:"usage"`
+}
+
+// UsageQuota is the tenant's use of its monthly scoring quota this month.
+type UsageQuota struct {
+	// Month is YYYY-MM, in UTC
+	Month string `json:"month"`
+	// Limit and Remaining are null when the tenant has no quota
+	Limit     *int64    `json:"limit"`
+	Used      int64     `json:"used"`
+	Remaining *int64    `json:"remaining"`
+	ResetsAt  time.Time `json:"resetsAt"`
+}
+
+type UsageTotals struct {
+	Requests int64 `json:"requests"`
+	// Billable requests count towards the monthly scoring quota
+	Billable int64 `json:"billable"`
+}
+
+type UsageEntry struct {
+	// Period is YYYY-MM-DD, or YYYY-MM for monthly reports
+	Period   string `json:"period"`
+	CallerID string `json:"callerId"`
+	// Endpoint is the route as "METHOD /path", or the gRPC method name
+	Endpoint string `json:"endpoint"`
+	Billable bool   `json:"billable"`
+	Requests int64  `json:"requests"`
+}
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"credit-scoring/pkg/errors"
+)
+
+// Score events that can be delivered to webhooks.
+const (
+	EventScoreCalculated = "credit_score_calculated"
+	EventScoreRefreshed  = "credit_score_refreshed"
+	EventScoreExpired    = "credit_score_expired"
+)
+
+var validEventTypes = map[string]bool{
+	EventScoreCalculated: true,
+	EventScoreRefreshed:  true,
+	EventScoreExpired:    true,
+}
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,max=255"`
+	EventTypes []string `json:"eventTypes" binding:"required,min=1"`
+	// Secret signs deliveries. One is generated if omitted.
+	Secret string `json:"secret" binding:"omitempty,min=16,max=255"`
+}
+
+func (r *CreateWebhookRequest) Validate() error {
+	var fields []errors.FieldError
+
+	// Whether the host is public is checked by the service, which resolves it
+	if u, err := url.Parse(r.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+		fields = append(fields, errors.FieldError{Field: "url", Message: "must be an absolute https URL"})
+	}
+
+	for _, t := range r.EventTypes {
+		if !validEventTypes[t] {
+			fields = append(fields, errors.FieldError{
+				Field:   "eventTypes",
+				Message: "must only contain " + strings.Join([]string{EventScoreCalculated, EventScoreRefreshed, EventScoreExpired}, ", "),
+			})
+			break
+		}
+	}
+
+	if len(fields) > 0 {
+		return errors.Validation("Request validation failed", fields...)
+	}
+
+	return nil
+}
+
+type WebhookSubscription struct {
+	ID         string   `json:"id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	// Secret is only returned when the subscription is created
+	Secret    string    `json:"secret,omitempty"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type WebhookDelivery struct {
+	ID            string           `json:"id"`
+	EventType     string           `json:"eventType"`
+	URL           string           `json:"url"`
+	Status        string           `json:"status"`
+	RetryCount    int              `json:"retryCount"`
+	NextAttemptAt *time.Time       `json:"nextAttemptAt,omitempty"`
+	DeliveredAt   *time.Time       `json:"deliveredAt,omitempty"`
+	FailureReason string           `json:"failureReason,omitempty"`
+	CreatedAt     time.Time        `json:"createdAt"`
+	Payload       json.RawMessage  `json:"payload,omitempty"`
+	Attempts      []WebhookAttempt `json:"attempts,omitempty"`
+}
+
+type WebhookAttempt struct {
+	Attempt     int       `json:"attempt"`
+	StatusCode  int       `json:"statusCode,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMs  int       `json:"durationMs"`
+	AttemptedAt time.Time `json:"attemptedAt"`
+}
+
+// WebhookEvent is the body POSTed to subscribers.
+type WebhookEvent struct {
+	ID        string       `json:"id"`
+	Type      string       `json:"type"`
+	CreatedAt time.Time    `json:"createdAt"`
+	Data      *CreditScore `json:"data"`
+}
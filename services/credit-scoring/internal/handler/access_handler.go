@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/authz"
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/service"
+	"credit-scoring/pkg/errors"
+)
+
+// AccessHandler manages the delegation grants the caller has given other
+// users to their credit data.
+type AccessHandler struct {
+	service *service.AccessService
+	logger  *zap.Logger
+}
+
+func NewAccessHandler(service *service.AccessService, logger *zap.Logger) *AccessHandler {
+	return &AccessHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateGrant delegates access to the caller's credit data to another user
+func (h *AccessHandler) CreateGrant(c *gin.Context) {
+	owner, err := grantOwner(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.CreateAccessGrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	if err := req.Validate(owner); err != nil {
+		c.Error(err)
+		return
+	}
+
+	grant, err := h.service.CreateGrant(c.Request.Context(), owner, &req)
+	if err != nil {
+		h.logger.Error("Failed to create access grant", zap.Error(err))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to create access grant"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Data:    grant,
+		Message: "Access grant created",
+	})
+}
+
+// ListGrants lists the grants the caller has made
+func (h *AccessHandler) ListGrants(c *gin.Context) {
+	owner, err := grantOwner(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	grants, err := h.service.ListGrants(c.Request.Context(), owner)
+	if err != nil {
+		h.logger.Error("Failed to list access grants", zap.Error(err))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to list access grants"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    grants,
+	})
+}
+
+// RevokeGrant revokes one of the caller's grants
+func (h *AccessHandler) RevokeGrant(c *gin.Context) {
+	owner, err := grantOwner(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.service.RevokeGrant(c.Request.Context(), owner, c.Param("id")); err != nil {
+		h.logger.Error("Failed to revoke access grant", zap.Error(err), zap.String("grantId", c.Param("id")))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to revoke access grant"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Access grant revoked",
+	})
+}
+
+// grantOwner is the caller, whose data the grants share. Tokens without a
+// userId claim have no data to share.
+func grantOwner(c *gin.Context) (string, error) {
+	if p := principal(c); p.UserID != "" {
+		return p.UserID, nil
+	}
+	return "", errors.Forbidden("Token has no userId")
+}
+
+// authorizeUser checks that the caller may act on userID's credit data with
+// perm. Every handler taking a user ID, from the path or the body, calls it
+// before touching the user's data.
+func authorizeUser(c *gin.Context, access *service.AccessService, perm authz.Permission, userID string) error {
+	err := access.AuthorizeUser(c.Request.Context(), service.AccessRequest{
+		Principal:  principal(c),
+		Permission: perm,
+		UserID:     userID,
+		Action:     c.Request.Method + " " + c.FullPath(),
+		RequestID:  c.GetString("requestID"),
+	})
+	if err != nil {
+		return serviceError(err, "INTERNAL_ERROR", "Failed to check access")
+	}
+	return nil
+}
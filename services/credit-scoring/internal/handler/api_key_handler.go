@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/service"
+)
+
+// APIKeyHandler lets admins issue and revoke partner API keys.
+type APIKeyHandler struct {
+	service *service.APIKeyService
+	logger  *zap.Logger
+}
+
+func NewAPIKeyHandler(service *service.APIKeyService, logger *zap.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateKey issues a partner API key. The key is only returned here.
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	key, err := h.service.CreateKey(c.Request.Context(), principal(c).UserID, &req)
+	if err != nil {
+		h.logger.Error("Failed to create API key", zap.Error(err))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to create API key"))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Data:    key,
+		Message: "API key created; store it now, it can't be shown again",
+	})
+}
+
+// ListKeys lists API keys, optionally of one tenant
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	var query dto.APIKeyQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	keys, err := h.service.ListKeys(c.Request.Context(), query.TenantID)
+	if err != nil {
+		h.logger.Error("Failed to list API keys", zap.Error(err))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to list API keys"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    keys,
+	})
+}
+
+// RevokeKey revokes an API key at once
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	if err := h.service.RevokeKey(c.Request.Context(), c.Param("id")); err != nil {
+		h.logger.Error("Failed to revoke API key", zap.Error(err), zap.String("keyId", c.Param("id")))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to revoke API key"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "API key revoked",
+	})
+}
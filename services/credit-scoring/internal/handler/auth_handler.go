@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/service"
+)
+
+// AuthHandler issues and revokes tokens.
+type AuthHandler struct {
+	service *service.AuthService
+	logger  *zap.Logger
+}
+
+func NewAuthHandler(service *service.AuthService, logger *zap.Logger) *AuthHandler {
+	return &AuthHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Login exchanges an email and password for an access and refresh token
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	tokens, err := h.service.Login(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to log in", zap.Error(err))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to log in"))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    tokens,
+	})
+}
+
+// Token exchanges client credentials for an access token
+func (h *AuthHandler) Token(c *gin.Context) {
+	var req dto.ClientTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	tokens, err := h.service.ClientToken(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to issue client token", zap.Error(err), zap.String("clientId", req.ClientID))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to issue token"))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    tokens,
+	})
+}
+
+// Refresh rotates a refresh token
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	tokens, err := h.service.Refresh(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to refresh token", zap.Error(err))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to refresh token"))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    tokens,
+	})
+}
+
+// Logout revokes the caller's access token and its session
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims := c.MustGet("claims").(jwt.MapClaims)
+
+	req := service.LogoutRequest{}
+	req.TokenID, _ = claims["jti"].(string)
+	req.SessionID, _ = claims["sid"].(string)
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		req.ExpiresAt = exp.Time
+	}
+
+	if err := h.service.Logout(c.Request.Context(), req); err != nil {
+		h.logger.Error("Failed to log out", zap.Error(err), zap.String("userId", principal(c).UserID))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to log out"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Logged out",
+	})
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"credit-scoring/internal/authz"
 	"credit-scoring/internal/dto"
 	"credit-scoring/internal/service"
 	"credit-scoring/pkg/errors"
@@ -13,12 +16,16 @@ import (
 
 type CreditHandler struct {
 	service *service.CreditScoringService
+	stream  *service.ScoreStream
+	access  *service.AccessService
 	logger  *zap.Logger
 }
 
-func NewCreditHandler(service *service.CreditScoringService, logger *zap.Logger) *CreditHandler {
+func NewCreditHandler(service *service.CreditScoringService, stream *service.ScoreStream, access *service.AccessService, logger *zap.Logger) *CreditHandler {
 	return &CreditHandler{
 		service: service,
+		stream:  stream,
+		access:  access,
 		logger:  logger,
 	}
 }
@@ -27,21 +34,25 @@ func NewCreditHandler(service *service.CreditScoringService, logger *zap.Logger)
 func (h *CreditHandler) CalculateScore(c *gin.Context) {
 	var req dto.CalculateScoreRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("Invalid request body", zap.Error(err))
-		c.JSON(http.StatusBadRequest, errors.NewAPIError("INVALID_REQUEST", err.Error()))
+		c.Error(bindingError(err))
 		return
 	}
 
 	// Validate request
 	if err := req.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, errors.NewAPIError("VALIDATION_ERROR", err.Error()))
+		c.Error(err)
+		return
+	}
+
+	if err := authorizeUser(c, h.access, authz.ScoreCalculate, req.UserID); err != nil {
+		c.Error(err)
 		return
 	}
 
 	score, err := h.service.CalculateScore(c.Request.Context(), &req)
 	if err != nil {
 		h.logger.Error("Failed to calculate score", zap.Error(err), zap.String("userId", req.UserID))
-		c.JSON(http.StatusInternalServerError, errors.NewAPIError("CALCULATION_ERROR", "Failed to calculate credit score"))
+		c.Error(serviceError(err, "CALCULATION_ERROR", "Failed to calculate credit score"))
 		return
 	}
 
@@ -56,14 +67,19 @@ func (h *CreditHandler) CalculateScore(c *gin.Context) {
 func (h *CreditHandler) GetScore(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, errors.NewAPIError("INVALID_REQUEST", "User ID is required"))
+		c.Error(errors.InvalidRequest("User ID is required"))
+		return
+	}
+
+	if err := authorizeUser(c, h.access, authz.ScoreRead, userID); err != nil {
+		c.Error(err)
 		return
 	}
 
 	score, err := h.service.GetScore(c.Request.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to get score", zap.Error(err), zap.String("userId", userID))
-		c.JSON(http.StatusNotFound, errors.NewAPIError("NOT_FOUND", "Credit score not found"))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to retrieve credit score"))
 		return
 	}
 
@@ -77,14 +93,30 @@ func (h *CreditHandler) GetScore(c *gin.Context) {
 func (h *CreditHandler) GetHistory(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, errors.NewAPIError("INVALID_REQUEST", "User ID is required"))
+		c.Error(errors.InvalidRequest("User ID is required"))
+		return
+	}
+
+	if err := authorizeUser(c, h.access, authz.HistoryRead, userID); err != nil {
+		c.Error(err)
 		return
 	}
 
-	history, err := h.service.GetHistory(c.Request.Context(), userID)
+	var query dto.HistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	history, err := h.service.GetHistory(c.Request.Context(), userID, &query)
 	if err != nil {
 		h.logger.Error("Failed to get history", zap.Error(err), zap.String("userId", userID))
-		c.JSON(http.StatusInternalServerError, errors.NewAPIError("INTERNAL_ERROR", "Failed to retrieve history"))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to retrieve history"))
 		return
 	}
 
@@ -94,18 +126,60 @@ func (h *CreditHandler) GetHistory(c *gin.Context) {
 	})
 }
 
+// GetTrend retrieves time-bucketed score statistics for a user
+func (h *CreditHandler) GetTrend(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		c.Error(errors.InvalidRequest("User ID is required"))
+		return
+	}
+
+	if err := authorizeUser(c, h.access, authz.HistoryRead, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	var query dto.TrendQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	trend, err := h.service.GetTrend(c.Request.Context(), userID, &query)
+	if err != nil {
+		h.logger.Error("Failed to get trend", zap.Error(err), zap.String("userId", userID))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to retrieve trend"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    trend,
+	})
+}
+
 // RefreshScore forces a refresh of the credit score
 func (h *CreditHandler) RefreshScore(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, errors.NewAPIError("INVALID_REQUEST", "User ID is required"))
+		c.Error(errors.InvalidRequest("User ID is required"))
+		return
+	}
+
+	if err := authorizeUser(c, h.access, authz.ScoreRefresh, userID); err != nil {
+		c.Error(err)
 		return
 	}
 
 	score, err := h.service.RefreshScore(c.Request.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to refresh score", zap.Error(err), zap.String("userId", userID))
-		c.JSON(http.StatusInternalServerError, errors.NewAPIError("REFRESH_ERROR", "Failed to refresh credit score"))
+		c.Error(serviceError(err, "REFRESH_ERROR", "Failed to refresh credit score"))
 		return
 	}
 
@@ -115,3 +189,75 @@ func (h *CreditHandler) RefreshScore(c *gin.Context) {
 		Message: "Credit score refreshed successfully",
 	})
 }
+
+// streamHeartbeat keeps idle SSE connections from being closed by proxies
+const streamHeartbeat = 30 * time.Second
+
+// StreamScore pushes the user's credit score as server-sent events: the
+// current score first, if there is one, then every new or refreshed score.
+func (h *CreditHandler) StreamScore(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		c.Error(errors.InvalidRequest("User ID is required"))
+		return
+	}
+
+	if err := authorizeUser(c, h.access, authz.ScoreRead, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Subscribe before reading the current score so no update is missed
+	updates, unsubscribe := h.stream.Subscribe(principal(c).TenantID, userID)
+	defer unsubscribe()
+
+	ctx := c.Request.Context()
+	current, err := h.service.GetScore(ctx, userID)
+	if err != nil && !errors.IsKind(err, errors.KindNotFound) {
+		h.logger.Error("Failed to get score", zap.Error(err), zap.String("userId", userID))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to retrieve credit score"))
+		return
+	}
+
+	// The server's write timeout would otherwise end the stream
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Warn("Failed to clear write deadline", zap.Error(err))
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if current != nil {
+		c.SSEvent("score", current)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case score, ok := <-updates:
+			if !ok {
+				return false
+			}
+			c.SSEvent("score", score)
+		case <-heartbeat.C:
+			// SSE comment lines are ignored by clients
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// principal returns the caller set by middleware.Auth
+func principal(c *gin.Context) authz.Principal {
+	return c.MustGet("principal").(authz.Principal)
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/authz"
+	"credit-scoring/internal/dto"
+	"credit-scoring/pkg/errors"
+)
+
+// CalculateScoreV2 calculates credit score for a user and returns the v2
+// representation
+func (h *CreditHandler) CalculateScoreV2(c *gin.Context) {
+	var req dto.CalculateScoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := authorizeUser(c, h.access, authz.ScoreCalculate, req.UserID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	score, err := h.service.CalculateScoreV2(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to calculate score", zap.Error(err), zap.String("userId", req.UserID))
+		c.Error(serviceError(err, "CALCULATION_ERROR", "Failed to calculate credit score"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    score,
+		Message: "Credit score calculated successfully",
+	})
+}
+
+// GetScoreV2 retrieves the current credit score for a user
+func (h *CreditHandler) GetScoreV2(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		c.Error(errors.InvalidRequest("User ID is required"))
+		return
+	}
+
+	if err := authorizeUser(c, h.access, authz.ScoreRead, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	score, err := h.service.GetScoreV2(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get score", zap.Error(err), zap.String("userId", userID))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to retrieve credit score"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    score,
+	})
+}
+
+// GetHistoryV2 retrieves credit score history for a user
+func (h *CreditHandler) GetHistoryV2(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		c.Error(errors.InvalidRequest("User ID is required"))
+		return
+	}
+
+	if err := authorizeUser(c, h.access, authz.HistoryRead, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	var query dto.HistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	history, err := h.service.GetHistoryV2(c.Request.Context(), userID, &query)
+	if err != nil {
+		h.logger.Error("Failed to get history", zap.Error(err), zap.String("userId", userID))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to retrieve history"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    history,
+	})
+}
+
+// RefreshScoreV2 forces a refresh of the credit score
+func (h *CreditHandler) RefreshScoreV2(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		c.Error(errors.InvalidRequest("User ID is required"))
+		return
+	}
+
+	if err := authorizeUser(c, h.access, authz.ScoreRefresh, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	score, err := h.service.RefreshScoreV2(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to refresh score", zap.Error(err), zap.String("userId", userID))
+		c.Error(serviceError(err, "REFRESH_ERROR", "Failed to refresh credit score"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    score,
+		Message: "Credit score refreshed successfully",
+	})
+}
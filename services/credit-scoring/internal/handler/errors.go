@@ -0,0 +1,69 @@
+package handler
+
+import (
+	stderrors "errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"credit-scoring/pkg/errors"
+)
+
+func init() {
+	// Report binding errors using the JSON/query names clients send
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			for _, tag := range []string{"json", "form"} {
+				if name, _, _ := strings.Cut(f.Tag.Get(tag), ","); name != "" && name != "-" {
+					return name
+				}
+			}
+			return f.Name
+		})
+	}
+}
+
+// bindingError converts a gin binding error into a domain validation error
+func bindingError(err error) error {
+	var verrs validator.ValidationErrors
+	if !stderrors.As(err, &verrs) {
+		return errors.InvalidRequest("Malformed request: " + err.Error())
+	}
+
+	fields := make([]errors.FieldError, len(verrs))
+	for i, fe := range verrs {
+		fields[i] = errors.FieldError{
+			Field:   fe.Field(),
+			Message: validationMessage(fe),
+		}
+	}
+	return errors.Validation("Request validation failed", fields...)
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "datetime":
+		return "must be a date in " + fe.Param() + " format"
+	default:
+		return "failed " + fe.Tag() + " validation"
+	}
+}
+
+// serviceError passes domain errors through and reports anything else as an
+// internal error with the given code and message
+func serviceError(err error, code, message string) error {
+	if _, ok := errors.As(err); ok {
+		return err
+	}
+	return errors.Internal(message, err).WithCode(code)
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/service"
+	"credit-scoring/internal/tenant"
+	"credit-scoring/pkg/errors"
+	"credit-scoring/pkg/kafka"
+)
+
+// Kinds of upstream event that trigger a score recalculation.
+const (
+	EventLoanRepaid    = "loan-repaid"
+	EventLoanDefaulted = "loan-defaulted"
+	EventIncomeUpdated = "income-updated"
+)
+
+type EventHandler struct {
+	service *service.CreditScoringService
+	// kinds maps each consumed topic to the kind of event it carries
+	kinds  map[string]string
+	logger *zap.Logger
+}
+
+// NewEventHandler handles events from the topics in kinds, which maps each
+// topic to the kind of event it carries.
+func NewEventHandler(service *service.CreditScoringService, kinds map[string]string, logger *zap.Logger) (*EventHandler, error) {
+	for topic, kind := range kinds {
+		switch kind {
+		case EventLoanRepaid, EventLoanDefaulted, EventIncomeUpdated:
+		default:
+			return nil, fmt.Errorf("topic %s: unknown event kind %q", topic, kind)
+		}
+	}
+
+	return &EventHandler{
+		service: service,
+		kinds:   kinds,
+		logger:  logger,
+	}, nil
+}
+
+// Handle maps an upstream event to the affected user and tenant and
+// recalculates their score
+func (h *EventHandler) Handle(ctx context.Context, msg kafka.Message) error {
+	var event dto.UpstreamEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return kafka.Permanent(fmt.Errorf("invalid event payload: %w", err))
+	}
+
+	userID := event.UserID
+	if userID == "" {
+		userID = string(msg.Key)
+	}
+	if userID == "" {
+		return kafka.Permanent(fmt.Errorf("event has no user ID"))
+	}
+
+	tenantID := event.TenantID
+	if tenantID == "" {
+		tenantID = tenant.Default
+	}
+	ctx = tenant.NewContext(ctx, tenantID)
+
+	eventID := event.EventID
+	if eventID == "" {
+		eventID = fmt.Sprintf("%s-%d-%d", msg.Topic, msg.Partition, msg.Offset)
+	}
+
+	// Replayed dead letters arrive on the replay topic
+	topic := msg.Topic
+	if original, ok := kafka.Header(msg, kafka.HeaderDLQOriginalTopic); ok {
+		topic = original
+	}
+
+	apply, err := h.mutation(topic, &event)
+	if err != nil {
+		return kafka.Permanent(err)
+	}
+
+	score, err := h.service.RecalculateScore(ctx, eventID, userID, apply)
+	if errors.IsKind(err, errors.KindNotFound) {
+		return kafka.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	if score != nil {
+		h.logger.Info("Recalculated credit score from event",
+			zap.String("topic", topic),
+			zap.String("eventId", eventID),
+			zap.String("tenantId", tenantID),
+			zap.String("userId", userID),
+			zap.Int("score", score.Score),
+		)
+	}
+
+	return nil
+}
+
+// mutation returns how an event from topic changes the user's scoring inputs
+func (h *EventHandler) mutation(topic string, event *dto.UpstreamEvent) (func(req *dto.CalculateScoreRequest), error) {
+	kind, ok := h.kinds[topic]
+	if !ok {
+		return nil, fmt.Errorf("unsupported topic: %s", topic)
+	}
+
+	occurredAt := event.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	switch kind {
+	case EventLoanRepaid, EventLoanDefaulted:
+		if event.LoanID == "" {
+			return nil, fmt.Errorf("%s event has no loan ID", kind)
+		}
+		status := "paid"
+		if kind == EventLoanDefaulted {
+			status = "defaulted"
+		}
+		return func(req *dto.CalculateScoreRequest) {
+			req.LoanHistory = upsertLoan(req.LoanHistory, dto.LoanHistoryItem{
+				LoanID:      event.LoanID,
+				Amount:      event.Amount,
+				Status:      status,
+				PaymentDate: occurredAt,
+			})
+		}, nil
+	case EventIncomeUpdated:
+		return func(req *dto.CalculateScoreRequest) {
+			if event.IncomeAmount != nil {
+				req.IncomeAmount = *event.IncomeAmount
+			}
+			if event.EmploymentStatus != "" {
+				req.EmploymentStatus = event.EmploymentStatus
+			}
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported event kind: %s", kind)
+	}
+}
+
+// upsertLoan replaces the loan in history with the same ID, so a loan counts
+// once however many events it gets, or appends it if the loan is new. An
+// event without an amount keeps the amount already recorded.
+func upsertLoan(history []dto.LoanHistoryItem, loan dto.LoanHistoryItem) []dto.LoanHistoryItem {
+	for i := range history {
+		if history[i].LoanID == loan.LoanID {
+			if loan.Amount == 0 {
+				loan.Amount = history[i].Amount
+			}
+			history[i] = loan
+			return history
+		}
+	}
+	return append(history, loan)
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/dto"
+)
+
+// newTestEventHandler handles topics named after their event kinds.
+func newTestEventHandler(t *testing.T) *EventHandler {
+	t.Helper()
+	h, err := NewEventHandler(nil, map[string]string{
+		EventLoanRepaid:    EventLoanRepaid,
+		EventLoanDefaulted: EventLoanDefaulted,
+		EventIncomeUpdated: EventIncomeUpdated,
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestEventMutationUpsertsLoans(t *testing.T) {
+	h := newTestEventHandler(t)
+
+	tests := []struct {
+		name    string
+		topic   string
+		event   dto.UpstreamEvent
+		history []dto.LoanHistoryItem
+		want    []dto.LoanHistoryItem
+		wantErr bool
+	}{
+		{
+			name:  "repayment updates the loan",
+			topic: EventLoanRepaid,
+			event: dto.UpstreamEvent{LoanID: "loan-1"},
+			history: []dto.LoanHistoryItem{
+				{LoanID: "loan-1", Amount: 5000, Status: "active"},
+				{LoanID: "loan-2", Amount: 1000, Status: "paid"},
+			},
+			want: []dto.LoanHistoryItem{
+				{LoanID: "loan-1", Amount: 5000, Status: "paid"},
+				{LoanID: "loan-2", Amount: 1000, Status: "paid"},
+			},
+		},
+		{
+			name:    "repeated repayment counts once",
+			topic:   EventLoanRepaid,
+			event:   dto.UpstreamEvent{LoanID: "loan-1", Amount: 5000},
+			history: []dto.LoanHistoryItem{{LoanID: "loan-1", Amount: 5000, Status: "paid"}},
+			want:    []dto.LoanHistoryItem{{LoanID: "loan-1", Amount: 5000, Status: "paid"}},
+		},
+		{
+			name:    "default of an unknown loan adds it",
+			topic:   EventLoanDefaulted,
+			event:   dto.UpstreamEvent{LoanID: "loan-3", Amount: 700},
+			history: []dto.LoanHistoryItem{{LoanID: "loan-1", Amount: 5000, Status: "paid"}},
+			want: []dto.LoanHistoryItem{
+				{LoanID: "loan-1", Amount: 5000, Status: "paid"},
+				{LoanID: "loan-3", Amount: 700, Status: "defaulted"},
+			},
+		},
+		{
+			name:    "loan event without a loan ID is rejected",
+			topic:   EventLoanRepaid,
+			event:   dto.UpstreamEvent{Amount: 5000},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apply, err := h.mutation(tt.topic, &tt.event)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := &dto.CalculateScoreRequest{LoanHistory: tt.history}
+			apply(req)
+
+			if len(req.LoanHistory) != len(tt.want) {
+				t.Fatalf("got %d loans, want %d", len(req.LoanHistory), len(tt.want))
+			}
+			for i, got := range req.LoanHistory {
+				want := tt.want[i]
+				if got.LoanID != want.LoanID || got.Amount != want.Amount || got.Status != want.Status {
+					t.Errorf("loan %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEventMutationUpdatesIncome(t *testing.T) {
+	h := newTestEventHandler(t)
+	income := 4200.0
+	zero := 0.0
+
+	tests := []struct {
+		name           string
+		event          dto.UpstreamEvent
+		wantIncome     float64
+		wantEmployment string
+	}{
+		{
+			name:           "income and employment",
+			event:          dto.UpstreamEvent{IncomeAmount: &income, EmploymentStatus: "self-employed"},
+			wantIncome:     4200,
+			wantEmployment: "self-employed",
+		},
+		{
+			name:           "employment only keeps income",
+			event:          dto.UpstreamEvent{EmploymentStatus: "unemployed"},
+			wantIncome:     3000,
+			wantEmployment: "unemployed",
+		},
+		{
+			name:           "income only keeps employment",
+			event:          dto.UpstreamEvent{IncomeAmount: &income},
+			wantIncome:     4200,
+			wantEmployment: "employed",
+		},
+		{
+			name:           "income can drop to zero",
+			event:          dto.UpstreamEvent{IncomeAmount: &zero},
+			wantIncome:     0,
+			wantEmployment: "employed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apply, err := h.mutation(EventIncomeUpdated, &tt.event)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := &dto.CalculateScoreRequest{IncomeAmount: 3000, EmploymentStatus: "employed"}
+			apply(req)
+
+			if req.IncomeAmount != tt.wantIncome || req.EmploymentStatus != tt.wantEmployment {
+				t.Errorf("got income %v and %q, want %v and %q", req.IncomeAmount, req.EmploymentStatus, tt.wantIncome, tt.wantEmployment)
+			}
+		})
+	}
+}
+
+func TestEventHandlerTopics(t *testing.T) {
+	if _, err := NewEventHandler(nil, map[string]string{"loan-closed": "loan-closed"}, zap.NewNop()); err == nil {
+		t.Error("topic of an unknown event kind was accepted")
+	}
+
+	h, err := NewEventHandler(nil, map[string]string{
+		"prod.loans.repaid":  EventLoanRepaid,
+		"prod.income.change": EventIncomeUpdated,
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apply, err := h.mutation("prod.loans.repaid", &dto.UpstreamEvent{LoanID: "loan-1"})
+	if err != nil {
+		t.Fatalf("renamed topic: %v", err)
+	}
+	req := &dto.CalculateScoreRequest{LoanHistory: []dto.LoanHistoryItem{{LoanID: "loan-1", Status: "active"}}}
+	apply(req)
+	if req.LoanHistory[0].Status != "paid" {
+		t.Errorf("loan status = %q, want paid", req.LoanHistory[0].Status)
+	}
+
+	// Topics are dispatched by configuration, not by their names
+	if _, err := h.mutation(EventLoanRepaid, &dto.UpstreamEvent{LoanID: "loan-1"}); err == nil {
+		t.Error("unconfigured topic was accepted")
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/service"
+)
+
+type ExportHandler struct {
+	service *service.ExportService
+	logger  *zap.Logger
+}
+
+func NewExportHandler(service *service.ExportService, logger *zap.Logger) *ExportHandler {
+	return &ExportHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateExport writes a credit score extract to the configured destination
+func (h *ExportHandler) CreateExport(c *gin.Context) {
+	var req dto.ExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Large extracts outlast the server's write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Warn("Failed to clear write deadline for export", zap.Error(err))
+	}
+
+	result, err := h.service.Export(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to export scores", zap.Error(err), zap.String("job", req.Job))
+		c.Error(serviceError(err, "EXPORT_ERROR", "Failed to export credit scores"))
+		return
+	}
+
+	message := "Export completed"
+	if result.Rows == 0 {
+		message = "No new scores to export"
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    result,
+		Message: message,
+	})
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"credit-scoring/internal/authz"
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/middleware"
+	"credit-scoring/internal/service"
+	"credit-scoring/pkg/errors"
+	pb "credit-scoring/pkg/pb/creditscoringv1"
+)
+
+// CreditGRPCHandler serves CreditScoringService over gRPC. Requests are
+// validated with the same rules as the REST handlers.
+type CreditGRPCHandler struct {
+	pb.UnimplementedCreditScoringServiceServer
+	service *service.CreditScoringService
+	access  *service.AccessService
+	logger  *zap.Logger
+}
+
+func NewCreditGRPCHandler(service *service.CreditScoringService, access *service.AccessService, logger *zap.Logger) *CreditGRPCHandler {
+	return &CreditGRPCHandler{
+		service: service,
+		access:  access,
+		logger:  logger,
+	}
+}
+
+func (h *CreditGRPCHandler) CalculateScore(ctx context.Context, in *pb.CalculateScoreRequest) (*pb.CreditScore, error) {
+	req := dto.CalculateScoreRequest{
+		UserID:           in.GetUserId(),
+		IncomeAmount:     in.GetIncomeAmount(),
+		EmploymentStatus: in.GetEmploymentStatus(),
+		AccountAge:       int(in.GetAccountAge()),
+		TransactionData:  in.GetTransactionData().AsMap(),
+	}
+	for _, item := range in.GetLoanHistory() {
+		loan := dto.LoanHistoryItem{
+			Amount: item.GetAmount(),
+			Status: item.GetStatus(),
+		}
+		if item.GetPaymentDate() != nil {
+			loan.PaymentDate = item.GetPaymentDate().AsTime()
+		}
+		req.LoanHistory = append(req.LoanHistory, loan)
+	}
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return nil, bindingError(err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := h.authorizeUser(ctx, authz.ScoreCalculate, req.UserID); err != nil {
+		return nil, err
+	}
+
+	score, err := h.service.CalculateScore(ctx, &req)
+	if err != nil {
+		h.logger.Error("Failed to calculate score", zap.Error(err), zap.String("userId", req.UserID))
+		return nil, serviceError(err, "CALCULATION_ERROR", "Failed to calculate credit score")
+	}
+
+	return toPBCreditScore(score), nil
+}
+
+func (h *CreditGRPCHandler) GetScore(ctx context.Context, in *pb.GetScoreRequest) (*pb.CreditScore, error) {
+	if in.GetUserId() == "" {
+		return nil, errors.InvalidRequest("User ID is required")
+	}
+
+	if err := h.authorizeUser(ctx, authz.ScoreRead, in.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	score, err := h.service.GetScore(ctx, in.GetUserId())
+	if err != nil {
+		h.logger.Error("Failed to get score", zap.Error(err), zap.String("userId", in.GetUserId()))
+		return nil, serviceError(err, "INTERNAL_ERROR", "Failed to retrieve credit score")
+	}
+
+	return toPBCreditScore(score), nil
+}
+
+func (h *CreditGRPCHandler) GetHistory(ctx context.Context, in *pb.GetHistoryRequest) (*pb.GetHistoryResponse, error) {
+	if in.GetUserId() == "" {
+		return nil, errors.InvalidRequest("User ID is required")
+	}
+
+	if err := h.authorizeUser(ctx, authz.HistoryRead, in.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	query := dto.HistoryQuery{
+		Limit:  int(in.GetLimit()),
+		Cursor: in.GetCursor(),
+		Grade:  in.GetGrade(),
+	}
+
+	var fields []errors.FieldError
+	for _, d := range []struct {
+		field string
+		value string
+		dst   *time.Time
+	}{
+		{"from", in.GetFrom(), &query.From},
+		{"to", in.GetTo(), &query.To},
+	} {
+		if d.value == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", d.value)
+		if err != nil {
+			fields = append(fields, errors.FieldError{Field: d.field, Message: "must be a date in YYYY-MM-DD format"})
+			continue
+		}
+		*d.dst = t
+	}
+	if len(fields) > 0 {
+		return nil, errors.Validation("Query validation failed", fields...)
+	}
+
+	if err := binding.Validator.ValidateStruct(&query); err != nil {
+		return nil, bindingError(err)
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	history, err := h.service.GetHistory(ctx, in.GetUserId(), &query)
+	if err != nil {
+		h.logger.Error("Failed to get history", zap.Error(err), zap.String("userId", in.GetUserId()))
+		return nil, serviceError(err, "INTERNAL_ERROR", "Failed to retrieve history")
+	}
+
+	resp := &pb.GetHistoryResponse{
+		UserId:     history.UserID,
+		NextCursor: history.NextCursor,
+	}
+	for i := range history.History {
+		resp.History = append(resp.History, toPBCreditScore(&history.History[i]))
+	}
+
+	return resp, nil
+}
+
+func (h *CreditGRPCHandler) RefreshScore(ctx context.Context, in *pb.RefreshScoreRequest) (*pb.CreditScore, error) {
+	if in.GetUserId() == "" {
+		return nil, errors.InvalidRequest("User ID is required")
+	}
+
+	if err := h.authorizeUser(ctx, authz.ScoreRefresh, in.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	score, err := h.service.RefreshScore(ctx, in.GetUserId())
+	if err != nil {
+		h.logger.Error("Failed to refresh score", zap.Error(err), zap.String("userId", in.GetUserId()))
+		return nil, serviceError(err, "REFRESH_ERROR", "Failed to refresh credit score")
+	}
+
+	return toPBCreditScore(score), nil
+}
+
+// authorizeUser is the gRPC counterpart of the REST handlers' authorizeUser
+func (h *CreditGRPCHandler) authorizeUser(ctx context.Context, perm authz.Permission, userID string) error {
+	method, _ := grpc.Method(ctx)
+	err := h.access.AuthorizeUser(ctx, service.AccessRequest{
+		Principal:  authz.FromContext(ctx),
+		Permission: perm,
+		UserID:     userID,
+		Action:     method,
+		RequestID:  middleware.RequestIDFromContext(ctx),
+	})
+	if err != nil {
+		return serviceError(err, "INTERNAL_ERROR", "Failed to check access")
+	}
+	return nil
+}
+
+func toPBCreditScore(score *dto.CreditScore) *pb.CreditScore {
+	return &pb.CreditScore{
+		Id:             score.ID,
+		UserId:         score.UserID,
+		Score:          int32(score.Score),
+		Grade:          score.Grade,
+		Factors:        score.Factors,
+		Recommendation: score.Recommendation,
+		CalculatedAt:   timestamppb.New(score.CalculatedAt),
+		ExpiresAt:      timestamppb.New(score.ExpiresAt),
+	}
+}
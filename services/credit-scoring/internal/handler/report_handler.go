@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/authz"
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/report"
+	"credit-scoring/internal/service"
+	"credit-scoring/pkg/errors"
+)
+
+type ReportHandler struct {
+	service *service.ReportService
+	access  *service.AccessService
+	logger  *zap.Logger
+}
+
+func NewReportHandler(service *service.ReportService, access *service.AccessService, logger *zap.Logger) *ReportHandler {
+	return &ReportHandler{
+		service: service,
+		access:  access,
+		logger:  logger,
+	}
+}
+
+// GetReport renders a downloadable credit report as PDF or HTML
+func (h *ReportHandler) GetReport(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		c.Error(errors.InvalidRequest("User ID is required"))
+		return
+	}
+
+	if err := authorizeUser(c, h.access, authz.ReportRead, userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	var query dto.ReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+	if query.Format == "" {
+		query.Format = dto.ReportFormatPDF
+	}
+
+	creditReport, err := h.service.GetReport(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to build report", zap.Error(err), zap.String("userId", userID))
+		c.Error(serviceError(err, "REPORT_ERROR", "Failed to build credit report"))
+		return
+	}
+
+	// Render fully before writing so a failure can still be reported as JSON
+	var buf bytes.Buffer
+	contentType, err := report.Render(&buf, query.Format, creditReport)
+	if err != nil {
+		h.logger.Error("Failed to render report", zap.Error(err), zap.String("userId", userID))
+		c.Error(errors.Internal("Failed to render credit report", err).WithCode("REPORT_ERROR"))
+		return
+	}
+
+	disposition := "inline"
+	if query.Format == dto.ReportFormatPDF {
+		disposition = "attachment"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition,
+		fmt.Sprintf("credit-report-%s-%s.%s", userID, creditReport.GeneratedAt.Format("2006-01-02"), query.Format)))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/authz"
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/service"
+	"credit-scoring/pkg/errors"
+)
+
+// UsageHandler reports tenants' API usage and scoring quotas.
+type UsageHandler struct {
+	service *service.UsageService
+	logger  *zap.Logger
+}
+
+func NewUsageHandler(service *service.UsageService, logger *zap.Logger) *UsageHandler {
+	return &UsageHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetUsage reports the caller's tenant's usage, or for admins that of any
+// tenant
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	var query dto.UsageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	caller := principal(c)
+	tenantID := caller.TenantID
+	if query.TenantID != "" && query.TenantID != tenantID {
+		if caller.Role != authz.RoleAdmin {
+			c.Error(errors.Forbidden("Only admins may report on other tenants"))
+			return
+		}
+		tenantID = query.TenantID
+	}
+
+	report, err := h.service.Report(c.Request.Context(), tenantID, &query)
+	if err != nil {
+		h.logger.Error("Failed to get usage", zap.Error(err), zap.String("tenantId", tenantID))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to retrieve usage"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    report,
+	})
+}
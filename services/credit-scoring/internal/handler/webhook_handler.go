@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/service"
+)
+
+// WebhookHandler manages the caller's webhook subscriptions. Subscriptions
+// are scoped to the authenticated user that created them.
+type WebhookHandler struct {
+	service *service.WebhookService
+	logger  *zap.Logger
+}
+
+func NewWebhookHandler(service *service.WebhookService, logger *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateSubscription registers a webhook and returns its signing secret
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindingError(err))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	sub, err := h.service.CreateSubscription(c.Request.Context(), ownerID(c), &req)
+	if err != nil {
+		h.logger.Error("Failed to create webhook subscription", zap.Error(err))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to create webhook subscription"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Data:    sub,
+		Message: "Webhook subscription created; store the secret, it is not shown again",
+	})
+}
+
+// ListSubscriptions lists the caller's webhook subscriptions
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	subs, err := h.service.ListSubscriptions(c.Request.Context(), ownerID(c))
+	if err != nil {
+		h.logger.Error("Failed to list webhook subscriptions", zap.Error(err))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to list webhook subscriptions"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    subs,
+	})
+}
+
+// GetSubscription retrieves one of the caller's webhook subscriptions
+func (h *WebhookHandler) GetSubscription(c *gin.Context) {
+	sub, err := h.service.GetSubscription(c.Request.Context(), ownerID(c), c.Param("id"))
+	if err != nil {
+		h.logger.Error("Failed to get webhook subscription", zap.Error(err), zap.String("subscriptionId", c.Param("id")))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to retrieve webhook subscription"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    sub,
+	})
+}
+
+// DeleteSubscription removes a webhook subscription and its delivery log
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	if err := h.service.DeleteSubscription(c.Request.Context(), ownerID(c), c.Param("id")); err != nil {
+		h.logger.Error("Failed to delete webhook subscription", zap.Error(err), zap.String("subscriptionId", c.Param("id")))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to delete webhook subscription"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Message: "Webhook subscription deleted",
+	})
+}
+
+// ListDeliveries lists recent deliveries to a subscription
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	deliveries, err := h.service.ListDeliveries(c.Request.Context(), ownerID(c), c.Param("id"))
+	if err != nil {
+		h.logger.Error("Failed to list webhook deliveries", zap.Error(err), zap.String("subscriptionId", c.Param("id")))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to list webhook deliveries"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    deliveries,
+	})
+}
+
+// GetDelivery retrieves a delivery with its payload and attempt log
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	delivery, err := h.service.GetDelivery(c.Request.Context(), ownerID(c), c.Param("id"), c.Param("deliveryId"))
+	if err != nil {
+		h.logger.Error("Failed to get webhook delivery", zap.Error(err), zap.String("deliveryId", c.Param("deliveryId")))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to retrieve webhook delivery"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Data:    delivery,
+	})
+}
+
+// Redeliver queues a delivery to be sent again
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	if err := h.service.Redeliver(c.Request.Context(), ownerID(c), c.Param("id"), c.Param("deliveryId")); err != nil {
+		h.logger.Error("Failed to redeliver webhook", zap.Error(err), zap.String("deliveryId", c.Param("deliveryId")))
+		c.Error(serviceError(err, "INTERNAL_ERROR", "Failed to redeliver webhook"))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.SuccessResponse{
+		Success: true,
+		Message: "Webhook delivery queued",
+	})
+}
+
+// ownerID is the authenticated user making the request
+func ownerID(c *gin.Context) string {
+	return fmt.Sprint(c.MustGet("userId"))
+}
@@ -1,51 +1,131 @@
 package middleware
 
 import (
-	"net/http"
-	"strings"
+	"context"
+	"crypto/tls"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 
+	"credit-scoring/internal/authz"
+	"credit-scoring/internal/tenant"
 	"credit-scoring/pkg/errors"
+	"credit-scoring/pkg/mtls"
 )
 
-func Auth(jwtSecret string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, errors.NewAPIError("UNAUTHORIZED", "Missing authorization header"))
-			c.Abort()
-			return
-		}
+// APIKeyAuthenticator resolves a partner API key to the principal it acts
+// as.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (authz.Principal, error)
+}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, errors.NewAPIError("UNAUTHORIZED", "Invalid authorization format"))
-			c.Abort()
+// Auth authenticates the caller with a bearer token, with an API key in the
+// X-API-Key header if keys is set, or, when neither is sent, with a verified
+// client certificate whose identity is in certs.
+func Auth(verifier *TokenVerifier, keys APIKeyAuthenticator, certs mtls.Identities) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+			authenticateAPIKey(c, keys, apiKey)
 			return
 		}
 
-		tokenString := parts[1]
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
+		if c.GetHeader("Authorization") == "" {
+			principal, ok, err := certPrincipal(certs, c.Request.TLS)
+			if err != nil {
+				abortWithError(c, err)
+				return
+			}
+			if ok {
+				setPrincipal(c, principal)
+				c.Next()
+				return
 			}
-			return []byte(jwtSecret), nil
-		})
+		}
 
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, errors.NewAPIError("UNAUTHORIZED", "Invalid or expired token"))
-			c.Abort()
+		claims, err := verifier.ParseBearerToken(c.Request.Context(), c.GetHeader("Authorization"))
+		if err != nil {
+			abortWithError(c, err)
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			c.Set("userId", claims["userId"])
-			c.Set("email", claims["email"])
-			c.Set("role", claims["role"])
+		setPrincipal(c, principalFromClaims(claims))
+		c.Set("email", claims["email"])
+		c.Set("claims", claims)
+
+		c.Next()
+	}
+}
+
+func authenticateAPIKey(c *gin.Context, keys APIKeyAuthenticator, apiKey string) {
+	switch {
+	case keys == nil:
+		abortWithError(c, errors.Unauthorized("API keys are not accepted here"))
+		return
+	case c.GetHeader("Authorization") != "":
+		abortWithError(c, errors.Unauthorized("Send either an API key or a bearer token, not both"))
+		return
+	}
+
+	principal, err := keys.Authenticate(c.Request.Context(), apiKey)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	setPrincipal(c, principal)
+
+	c.Next()
+}
+
+// setPrincipal stores the caller for handlers and puts its tenant in the
+// request context, which scopes every query the request makes.
+func setPrincipal(c *gin.Context, principal authz.Principal) {
+	c.Set("userId", principal.UserID)
+	c.Set("role", principal.Role)
+	c.Set("principal", principal)
+	c.Request = c.Request.WithContext(tenant.NewContext(c.Request.Context(), principal.TenantID))
+}
+
+// certPrincipal returns the caller identified by a verified client
+// certificate. ok is false without one, or if certs is empty.
+func certPrincipal(certs mtls.Identities, state *tls.ConnectionState) (p authz.Principal, ok bool, err error) {
+	if len(certs) == 0 {
+		return authz.Principal{}, false, nil
+	}
+	cert, ok := mtls.VerifiedClientCert(state)
+	if !ok {
+		return authz.Principal{}, false, nil
+	}
+
+	identity, role, ok := certs.Identify(cert)
+	if !ok {
+		return authz.Principal{}, false, errors.Unauthorized("Client certificate identity is not allowed")
+	}
+	return authz.Principal{UserID: identity, Role: role, TenantID: tenant.Default}, true, nil
+}
+
+// Authorize rejects callers whose role lacks perm. Whether the caller may act
+// on the user a route names is checked by the handler. It must run after
+// Auth.
+func Authorize(perm authz.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := authz.Authorize(c.MustGet("principal").(authz.Principal), perm); err != nil {
+			abortWithError(c, err)
+			return
 		}
 
 		c.Next()
 	}
 }
+
+// principalFromClaims reads the caller from a token. Tokens without a
+// tenantId claim act for the default tenant.
+func principalFromClaims(claims jwt.MapClaims) authz.Principal {
+	userID, _ := claims["userId"].(string)
+	role, _ := claims["role"].(string)
+	tenantID, _ := claims["tenantId"].(string)
+	if tenantID == "" {
+		tenantID = tenant.Default
+	}
+	return authz.Principal{UserID: userID, Role: role, TenantID: tenantID}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"credit-scoring/pkg/errors"
+)
+
+// unavailableRetryAfter is how long clients are asked to wait before retrying
+// a request that failed because a dependency was unavailable.
+const unavailableRetryAfter = 5 * time.Second
+
+// Errors renders the last error attached with c.Error as an RFC 7807
+// application/problem+json response, unless a response was already written.
+func Errors(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		writeProblem(c, logger, c.Errors.Last().Err)
+	}
+}
+
+// abortWithError stops the handler chain and leaves err for Errors to render.
+func abortWithError(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
+
+func writeProblem(c *gin.Context, logger *zap.Logger, err error) {
+	problem := errors.ToProblem(err)
+	problem.Instance = c.Request.URL.Path
+	problem.RequestID = c.GetString("requestID")
+
+	if problem.Status >= 500 {
+		logger.Error("Request failed",
+			zap.Error(err),
+			zap.Int("status", problem.Status),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("request-id", problem.RequestID),
+		)
+	}
+
+	if problem.Status == http.StatusServiceUnavailable && c.Writer.Header().Get("Retry-After") == "" {
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(unavailableRetryAfter)))
+	}
+	c.Header("Content-Type", errors.ProblemContentType)
+	c.JSON(problem.Status, problem)
+}
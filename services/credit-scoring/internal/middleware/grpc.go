@@ -0,0 +1,370 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"credit-scoring/internal/authz"
+	"credit-scoring/internal/tenant"
+	"credit-scoring/pkg/errors"
+	"credit-scoring/pkg/mtls"
+)
+
+// The interceptors below give the gRPC server the same request ID, logging,
+// recovery, error, auth and metering behaviour as the gin middleware. The
+// stream variants cover the streaming methods, such as health Watch and
+// reflection.
+
+type contextKey string
+
+const (
+	requestIDKey contextKey = "requestID"
+	claimsKey    contextKey = "claims"
+)
+
+// RequestIDFromContext returns the request ID set by GRPCRequestID.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// ClaimsFromContext returns the JWT claims set by GRPCAuth.
+func ClaimsFromContext(ctx context.Context) (jwt.MapClaims, bool) {
+	claims, ok := ctx.Value(claimsKey).(jwt.MapClaims)
+	return claims, ok
+}
+
+// serverStream overrides the context of a stream, as the unary interceptors
+// do by passing a new one to the handler.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func GRPCRequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(requestIDContext(ctx), req)
+	}
+}
+
+func GRPCStreamRequestID() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: requestIDContext(ss.Context())})
+	}
+}
+
+func requestIDContext(ctx context.Context) context.Context {
+	requestID := firstMetadata(ctx, "x-request-id")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs("x-request-id", requestID))
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func GRPCLogger(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(logger, ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func GRPCStreamLogger(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(logger, ss.Context(), info.FullMethod, start, err)
+		return err
+	}
+}
+
+func logCall(logger *zap.Logger, ctx context.Context, method string, start time.Time, err error) {
+	var addr string
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+	}
+
+	logger.Info("gRPC Request",
+		zap.String("method", method),
+		zap.String("code", status.Code(err).String()),
+		zap.Duration("latency", time.Since(start)),
+		zap.String("ip", addr),
+		zap.String("user-agent", firstMetadata(ctx, "user-agent")),
+		zap.String("request-id", RequestIDFromContext(ctx)),
+	)
+}
+
+func GRPCRecovery(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer recoverCall(logger, info.FullMethod, &err)
+		return handler(ctx, req)
+	}
+}
+
+func GRPCStreamRecovery(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer recoverCall(logger, info.FullMethod, &err)
+		return handler(srv, ss)
+	}
+}
+
+// recoverCall must be deferred; it turns a panic into an Internal status.
+func recoverCall(logger *zap.Logger, method string, err *error) {
+	if r := recover(); r != nil {
+		logger.Error("Panic recovered",
+			zap.Any("error", r),
+			zap.String("method", method),
+		)
+		*err = status.Error(codes.Internal, "An unexpected error occurred")
+	}
+}
+
+var statusCodes = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.FailedPrecondition,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusInternalServerError: codes.Internal,
+	http.StatusBadGateway:          codes.Unavailable,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+}
+
+// conflictCodes are the conflicts that go away on their own, so the client
+// can retry; other conflicts are FailedPrecondition.
+var conflictCodes = map[string]codes.Code{
+	"IDEMPOTENCY_KEY_IN_USE": codes.Aborted,
+	"EXPORT_RUNNING":         codes.Aborted,
+}
+
+// GRPCErrors converts domain errors into gRPC statuses. The error code is sent
+// as ErrorInfo.Reason and validation failures as a BadRequest detail.
+func GRPCErrors(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		if _, ok := status.FromError(err); ok {
+			return resp, err
+		}
+		return nil, statusError(logger, ctx, info.FullMethod, err)
+	}
+}
+
+func GRPCStreamErrors(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		if err == nil {
+			return nil
+		}
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return statusError(logger, ss.Context(), info.FullMethod, err)
+	}
+}
+
+// statusError converts a domain error into a gRPC status error.
+func statusError(logger *zap.Logger, ctx context.Context, method string, err error) error {
+	problem := errors.ToProblem(err)
+	if problem.Status >= 500 {
+		logger.Error("Request failed",
+			zap.Error(err),
+			zap.String("method", method),
+			zap.String("request-id", RequestIDFromContext(ctx)),
+		)
+	}
+
+	code := statusCodes[problem.Status]
+	if c, ok := conflictCodes[problem.Code]; ok && problem.Status == http.StatusConflict {
+		code = c
+	}
+
+	st := status.New(code, problem.Detail)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: problem.Code, Domain: "credit-scoring"}}
+	if len(problem.Errors) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, f := range problem.Errors {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       f.Field,
+				Description: f.Message,
+			})
+		}
+		details = append(details, br)
+	}
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+
+	return st.Err()
+}
+
+// GRPCAuth checks the "authorization" metadata like Auth checks the header,
+// falling back to the client certificate when there is none. The caller and
+// its tenant are stored in the context. Methods with a prefix in public, such as health
+// checks, skip auth.
+func GRPCAuth(verifier *TokenVerifier, certs mtls.Identities, public ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isPublic(info.FullMethod, public) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, verifier, certs)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// GRPCStreamAuth does for streaming methods what GRPCAuth does for unary ones.
+func GRPCStreamAuth(verifier *TokenVerifier, certs mtls.Identities, public ...string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isPublic(info.FullMethod, public) {
+			return handler(srv, ss)
+		}
+
+		ctx, err := authenticate(ss.Context(), verifier, certs)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate returns ctx with the caller, from the bearer token or else the
+// client certificate, and its tenant.
+func authenticate(ctx context.Context, verifier *TokenVerifier, certs mtls.Identities) (context.Context, error) {
+	authHeader := firstMetadata(ctx, "authorization")
+	if authHeader == "" {
+		principal, ok, err := certPrincipal(certs, peerTLSState(ctx))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return principalContext(ctx, principal), nil
+		}
+	}
+
+	claims, err := verifier.ParseBearerToken(ctx, authHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, claimsKey, claims)
+	return principalContext(ctx, principalFromClaims(claims)), nil
+}
+
+func isPublic(method string, public []string) bool {
+	for _, prefix := range public {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// principalContext stores the caller and its tenant in ctx.
+func principalContext(ctx context.Context, principal authz.Principal) context.Context {
+	return tenant.NewContext(authz.NewContext(ctx, principal), principal.TenantID)
+}
+
+// peerTLSState returns the TLS connection state of a gRPC call, or nil over
+// plaintext.
+func peerTLSState(ctx context.Context) *tls.ConnectionState {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil
+	}
+	return &info.State
+}
+
+// GRPCAuthorize applies the same policy as Authorize to the caller GRPCAuth
+// stored. permissions maps full method names to the permission they need;
+// methods without one are denied unless they have a prefix in public.
+func GRPCAuthorize(permissions map[string]authz.Permission, public ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isPublic(info.FullMethod, public) {
+			return handler(ctx, req)
+		}
+
+		principal := authz.FromContext(ctx)
+
+		perm, ok := permissions[info.FullMethod]
+		if !ok {
+			return nil, errors.Forbidden("Insufficient permissions")
+		}
+		if err := authz.Authorize(principal, perm); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// GRPCMeter does for gRPC what Meter and ScoreQuota do for gin: it records
+// successful calls, and enforces the monthly scoring quota on the methods in
+// billable, which it records as billable. Methods with a prefix in public
+// aren't metered.
+func GRPCMeter(meter UsageMeter, billable map[string]bool, public ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isPublic(info.FullMethod, public) {
+			return handler(ctx, req)
+		}
+
+		principal := authz.FromContext(ctx)
+		if billable[info.FullMethod] {
+			if err := checkScoreQuota(ctx, meter, principal.TenantID, func(retryAfter time.Duration) {
+				grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(ceilSeconds(retryAfter))))
+			}); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := handler(ctx, req)
+		if err == nil {
+			meter.Record(ctx, principal.TenantID, principal.UserID, info.FullMethod, billable[info.FullMethod])
+		}
+		return resp, err
+	}
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"credit-scoring/pkg/errors"
+)
+
+func TestStatusErrorCodes(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{errors.Validation("Request validation failed"), codes.InvalidArgument},
+		{errors.NotFound("Credit score not found"), codes.NotFound},
+		{errors.Conflict("Key reused").WithCode("IDEMPOTENCY_KEY_REUSED"), codes.FailedPrecondition},
+		{errors.Conflict("Key in use").WithCode("IDEMPOTENCY_KEY_IN_USE"), codes.Aborted},
+		{errors.Conflict("Export running").WithCode("EXPORT_RUNNING"), codes.Aborted},
+		{errors.Unavailable("The database is unavailable", nil), codes.Unavailable},
+		{context.DeadlineExceeded, codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			err := statusError(zap.NewNop(), context.Background(), "/test", tt.err)
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("code = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
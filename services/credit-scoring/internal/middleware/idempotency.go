@@ -0,0 +1,210 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/model"
+	"credit-scoring/internal/repository"
+	"credit-scoring/internal/tenant"
+	"credit-scoring/pkg/errors"
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+	idempotencyLockTTL      = 30 * time.Second
+)
+
+// IdempotencyStore persists responses for requests sent with an Idempotency-Key.
+// Get returns repository.ErrNotFound when no response is stored for the key.
+type IdempotencyStore interface {
+	Get(ctx context.Context, principal, key string) (*model.IdempotencyKey, error)
+	Save(ctx context.Context, record *model.IdempotencyKey) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// IdempotencyLocks holds a lock per key while its request is processed, such
+// as *redis.RedisClient.
+type IdempotencyLocks interface {
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	DeleteIfEqual(ctx context.Context, key string, value interface{}) (bool, error)
+}
+
+// Idempotency replays the stored response when a request is retried with the
+// same Idempotency-Key header. Keys are scoped to the authenticated user and
+// their tenant, and reusing a key with a different request body is rejected
+// with 409. A Redis lock stops concurrent retries from running the handler
+// twice.
+func Idempotency(store IdempotencyStore, locks IdempotencyLocks, ttl time.Duration, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			abortWithError(c, errors.InvalidRequest(
+				fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength),
+			))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortWithError(c, errors.InvalidRequest("Failed to read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		userID, ok := c.Get("userId")
+		if !ok {
+			abortWithError(c, errors.Internal("Failed to process request", fmt.Errorf("idempotency requires an authenticated route")))
+			return
+		}
+
+		ctx := c.Request.Context()
+		tenantID, _ := tenant.FromContext(ctx)
+		principal := fmt.Sprint(userID)
+		requestHash := hashRequest(c.Request, body)
+
+		// replayed reports whether the request was answered from the store,
+		// or refused because the store couldn't tell whether it was a retry
+		replayed := func() bool {
+			record, err := store.Get(ctx, principal, key)
+			switch {
+			case err == nil:
+				replayResponse(c, record, requestHash)
+				return true
+			case err != repository.ErrNotFound:
+				abortWithError(c, errors.Unavailable("Please retry the request", fmt.Errorf("look up idempotency key: %w", err)))
+				return true
+			}
+			return false
+		}
+
+		if replayed() {
+			return
+		}
+
+		lockKey := fmt.Sprintf("idempotency_lock:%s:%s:%s", tenantID, principal, key)
+		// The request ID comes from the client, so the lock holds a token
+		// of its own
+		lockToken := uuid.New().String()
+		acquired, err := locks.SetNX(ctx, lockKey, lockToken, idempotencyLockTTL)
+		if err != nil {
+			abortWithError(c, errors.Unavailable("Please retry the request", fmt.Errorf("acquire idempotency lock: %w", err)))
+			return
+		}
+		if !acquired {
+			abortWithError(c, errors.Conflict(
+				"A request with this Idempotency-Key is already being processed",
+			).WithCode("IDEMPOTENCY_KEY_IN_USE"))
+			return
+		}
+		// Once the lock expires another request may hold it
+		defer locks.DeleteIfEqual(context.Background(), lockKey, lockToken)
+
+		// The previous holder may have finished between the lookup and the lock
+		if replayed() {
+			return
+		}
+
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// Errors are otherwise rendered by the Errors middleware after this
+		// returns, too late to be stored
+		if len(c.Errors) > 0 && !writer.Written() {
+			writeProblem(c, logger, c.Errors.Last().Err)
+		}
+
+		// Server errors are not stored so the client can retry them
+		status := writer.Status()
+		if !writer.Written() || status >= http.StatusInternalServerError {
+			return
+		}
+
+		// The response is stored even if the client has gone away
+		if err := store.Save(context.WithoutCancel(ctx), &model.IdempotencyKey{
+			Key:          key,
+			Principal:    principal,
+			RequestHash:  requestHash,
+			StatusCode:   status,
+			ResponseBody: writer.body.Bytes(),
+			ContentType:  writer.Header().Get("Content-Type"),
+			ExpiresAt:    time.Now().Add(ttl),
+		}); err != nil {
+			logger.Error("Failed to save idempotency key", zap.Error(err), zap.String("key", key))
+		}
+	}
+}
+
+// DeleteExpiredIdempotencyKeys removes expired keys from store every interval
+// until ctx is cancelled.
+func DeleteExpiredIdempotencyKeys(ctx context.Context, store IdempotencyStore, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := store.DeleteExpired(ctx); err != nil && ctx.Err() == nil {
+			logger.Warn("Failed to delete expired idempotency keys", zap.Error(err))
+		}
+	}
+}
+
+func replayResponse(c *gin.Context, record *model.IdempotencyKey, requestHash string) {
+	if record.RequestHash != requestHash {
+		abortWithError(c, errors.Conflict(
+			"Idempotency-Key was already used with a different request",
+		).WithCode("IDEMPOTENCY_KEY_REUSED"))
+		return
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(record.StatusCode, record.ContentType, record.ResponseBody)
+	c.Abort()
+}
+
+func hashRequest(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// bodyCaptureWriter keeps a copy of the response body as it is written.
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
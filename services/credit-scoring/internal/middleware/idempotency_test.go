@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/model"
+	"credit-scoring/internal/repository"
+	"credit-scoring/pkg/errors"
+)
+
+type fakeIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*model.IdempotencyKey
+	// err, if set, is returned by every lookup
+	err error
+}
+
+func (s *fakeIdempotencyStore) Get(ctx context.Context, principal, key string) (*model.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	if record, ok := s.records[principal+"/"+key]; ok {
+		return record, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (s *fakeIdempotencyStore) Save(ctx context.Context, record *model.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.Principal+"/"+record.Key] = record
+	return nil
+}
+
+func (s *fakeIdempotencyStore) DeleteExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+type fakeLocks struct {
+	mu    sync.Mutex
+	locks map[string]interface{}
+}
+
+func (l *fakeLocks) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, held := l.locks[key]; held {
+		return false, nil
+	}
+	l.locks[key] = value
+	return true, nil
+}
+
+func (l *fakeLocks) DeleteIfEqual(ctx context.Context, key string, value interface{}) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.locks[key] != value {
+		return false, nil
+	}
+	delete(l.locks, key)
+	return true, nil
+}
+
+func TestIdempotencyStoresResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		handler    gin.HandlerFunc
+		wantStatus int
+		wantStored bool
+		wantBody   string
+	}{
+		{
+			name: "success",
+			handler: func(c *gin.Context) {
+				c.JSON(http.StatusCreated, gin.H{"id": "cs_1"})
+			},
+			wantStatus: http.StatusCreated,
+			wantStored: true,
+			wantBody:   `"id":"cs_1"`,
+		},
+		{
+			name: "error left for the Errors middleware",
+			handler: func(c *gin.Context) {
+				c.Error(errors.Validation("Request validation failed", errors.FieldError{Field: "income", Message: "is required"}))
+			},
+			wantStatus: http.StatusBadRequest,
+			wantStored: true,
+			wantBody:   `"code":"VALIDATION_ERROR"`,
+		},
+		{
+			name: "server error",
+			handler: func(c *gin.Context) {
+				c.Error(errors.Internal("Failed to calculate score", nil))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "nothing written",
+			handler:    func(c *gin.Context) {},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeIdempotencyStore{records: map[string]*model.IdempotencyKey{}}
+			locks := &fakeLocks{locks: map[string]interface{}{}}
+
+			router := gin.New()
+			router.Use(Errors(zap.NewNop()))
+			router.POST("/score",
+				func(c *gin.Context) { c.Set("userId", "u1") },
+				Idempotency(store, locks, time.Hour, zap.NewNop()),
+				tt.handler,
+			)
+
+			send := func() *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodPost, "/score", strings.NewReader(`{"income":1}`))
+				req.Header.Set("Idempotency-Key", "key-1")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				return w
+			}
+
+			first := send()
+			if first.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", first.Code, tt.wantStatus)
+			}
+
+			record, err := store.Get(context.Background(), "u1", "key-1")
+			if stored := err == nil; stored != tt.wantStored {
+				t.Fatalf("stored = %v, want %v", stored, tt.wantStored)
+			}
+			if len(locks.locks) != 0 {
+				t.Errorf("lock not released")
+			}
+			if !tt.wantStored {
+				return
+			}
+
+			if record.StatusCode != tt.wantStatus || !strings.Contains(string(record.ResponseBody), tt.wantBody) {
+				t.Errorf("stored %d %s, want %d containing %s", record.StatusCode, record.ResponseBody, tt.wantStatus, tt.wantBody)
+			}
+
+			replay := send()
+			if replay.Code != tt.wantStatus || replay.Body.String() != first.Body.String() {
+				t.Errorf("replayed %d %s, want %d %s", replay.Code, replay.Body, first.Code, first.Body)
+			}
+			if replay.Header().Get("Idempotent-Replayed") != "true" {
+				t.Errorf("replay is missing the Idempotent-Replayed header")
+			}
+		})
+	}
+}
+
+func TestIdempotencyRequiresAuthenticatedRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := &fakeIdempotencyStore{records: map[string]*model.IdempotencyKey{}}
+	router := gin.New()
+	router.Use(Errors(zap.NewNop()))
+	router.POST("/score", Idempotency(store, &fakeLocks{locks: map[string]interface{}{}}, time.Hour, zap.NewNop()), func(c *gin.Context) {
+		t.Error("handler ran without a user")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/score", strings.NewReader(`{}`))
+	req.Header.Set("Idempotency-Key", "key-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestIdempotencyStoreUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := &fakeIdempotencyStore{
+		records: map[string]*model.IdempotencyKey{},
+		err:     errors.Unavailable("The database is unavailable", context.DeadlineExceeded),
+	}
+	locks := &fakeLocks{locks: map[string]interface{}{}}
+
+	router := gin.New()
+	router.Use(Errors(zap.NewNop()))
+	router.POST("/score",
+		func(c *gin.Context) { c.Set("userId", "u1") },
+		Idempotency(store, locks, time.Hour, zap.NewNop()),
+		func(c *gin.Context) {
+			t.Error("handler ran without knowing whether the request was a retry")
+		},
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/score", strings.NewReader(`{"income":1}`))
+	req.Header.Set("Idempotency-Key", "key-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if len(locks.locks) != 0 {
+		t.Errorf("lock not released")
+	}
+}
@@ -1,63 +1,139 @@
 package middleware
 
 import (
-	"net/http"
-	"sync"
+	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
 
+	"credit-scoring/internal/authz"
 	"credit-scoring/pkg/errors"
+	"credit-scoring/pkg/ratelimit"
 )
 
-type visitor struct {
-	limiter  *rate.Limiter
-	lastSeen time.Time
+// RateLimitPolicies are the limits RateLimit applies: Routes to the routes
+// they name, as "METHOD /path" with gin's path parameters, and Default to the
+// rest.
+type RateLimitPolicies struct {
+	Default ratelimit.Limit
+	Routes  map[string]ratelimit.Limit
 }
 
-var (
-	visitors = make(map[string]*visitor)
-	mu       sync.RWMutex
-)
+// ParseRateLimitPolicies parses the default limit and per-route limits, each
+// written as ratelimit.ParseLimit accepts.
+func ParseRateLimitPolicies(defaultLimit string, routes map[string]string) (RateLimitPolicies, error) {
+	policies := RateLimitPolicies{Routes: make(map[string]ratelimit.Limit)}
 
-func RateLimiter(rps int, burst int) gin.HandlerFunc {
-	// Cleanup old visitors every minute
-	go func() {
-		for {
-			time.Sleep(time.Minute)
-			mu.Lock()
-			for ip, v := range visitors {
-				if time.Since(v.lastSeen) > 3*time.Minute {
-					delete(visitors, ip)
-				}
-			}
-			mu.Unlock()
+	var err error
+	if policies.Default, err = ratelimit.ParseLimit(defaultLimit); err != nil {
+		return RateLimitPolicies{}, err
+	}
+	for route, limit := range routes {
+		if policies.Routes[route], err = ratelimit.ParseLimit(limit); err != nil {
+			return RateLimitPolicies{}, fmt.Errorf("%s: %w", route, err)
 		}
-	}()
+	}
 
+	return policies, nil
+}
+
+// CheckRoutes returns an error if a policy names a route that isn't
+// registered, so a typo can't silently leave a route on the default limit.
+func (p RateLimitPolicies) CheckRoutes(routes gin.RoutesInfo) error {
+	registered := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		registered[route.Method+" "+route.Path] = true
+	}
+	for route := range p.Routes {
+		if !registered[route] {
+			return fmt.Errorf("rate limit for unknown route %q", route)
+		}
+	}
+	return nil
+}
+
+// RateLimitIP limits every request from an IP address, before routing and
+// authentication, so floods and credential guessing are cut off cheaply.
+func RateLimitIP(limiter *ratelimit.Limiter, limit ratelimit.Limit) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		
-		mu.Lock()
-		v, exists := visitors[ip]
-		if !exists {
-			limiter := rate.NewLimiter(rate.Limit(rps), burst)
-			visitors[ip] = &visitor{limiter, time.Now()}
-			v = visitors[ip]
+		result := limiter.Allow(c.Request.Context(), "ip:"+c.ClientIP(), limit)
+		if !applyRateLimit(c, result, "Too many requests") {
+			return
 		}
-		v.lastSeen = time.Now()
-		mu.Unlock()
-
-		if !v.limiter.Allow() {
-			c.JSON(http.StatusTooManyRequests, errors.NewAPIError(
-				"RATE_LIMIT_EXCEEDED",
-				"Too many requests",
-			))
-			c.Abort()
+		c.Next()
+	}
+}
+
+// RateLimit limits each caller's requests to a route by the route's policy.
+// Routes without one share a single default limit per caller. Callers are
+// identified by their principal within its tenant, or by IP before
+// authentication. Requests made with an API key also count against the key's
+// own quota.
+//
+// Limits are shared by all replicas through limiter. Responses carry the
+// RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy
+// headers of the limit closest to running out, and rejected requests get
+// Retry-After.
+func RateLimit(limiter *ratelimit.Limiter, policies RateLimitPolicies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+		limit, ok := policies.Routes[route]
+		if !ok {
+			route, limit = "default", policies.Default
+		}
+
+		caller := "ip:" + c.ClientIP()
+		var quota int
+		if value, ok := c.Get("principal"); ok {
+			principal := value.(authz.Principal)
+			caller = "tenant:" + principal.TenantID + ":" + principal.UserID
+			quota = principal.RateLimit
+		}
+
+		result := limiter.Allow(c.Request.Context(), route+":"+caller, limit)
+		if !applyRateLimit(c, result, "Too many requests") {
 			return
 		}
 
+		if quota > 0 {
+			result = limiter.Allow(c.Request.Context(), "api_key:"+caller, ratelimit.Limit{Rate: quota, Period: time.Minute})
+			if !applyRateLimit(c, result, "API key quota exceeded") {
+				return
+			}
+		}
+
 		c.Next()
 	}
 }
+
+// applyRateLimit sets the rate limit headers to result if it is closer to
+// running out than the request's previous limits, and aborts the request if
+// result denies it.
+func applyRateLimit(c *gin.Context, result ratelimit.Result, message string) bool {
+	if previous, ok := c.Get("rateLimit"); !ok || result.Remaining < previous.(ratelimit.Result).Remaining || !result.Allowed {
+		c.Set("rateLimit", result)
+		setRateLimitHeaders(c, result)
+	}
+
+	if !result.Allowed {
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		abortWithError(c, errors.RateLimited(message))
+		return false
+	}
+	return true
+}
+
+func setRateLimitHeaders(c *gin.Context, result ratelimit.Result) {
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit.Rate))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", result.Limit.Rate, ceilSeconds(result.Limit.Period)))
+}
+
+// ceilSeconds rounds d up to whole seconds, the unit of the rate limit
+// headers
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
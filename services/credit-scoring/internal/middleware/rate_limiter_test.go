@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/authz"
+	"credit-scoring/pkg/ratelimit"
+)
+
+func TestRateLimitHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	policies := RateLimitPolicies{
+		Default: ratelimit.Limit{Rate: 10, Period: time.Second},
+		Routes: map[string]ratelimit.Limit{
+			"GET /score":   {Rate: 2, Period: time.Minute},
+			"GET /reports": {Rate: 100, Period: time.Minute},
+		},
+	}
+
+	tests := []struct {
+		name string
+		path string
+		// keyQuota is the API key's requests per minute, if any
+		keyQuota int
+		// requests are made in a row; the last one is checked
+		requests    int
+		wantStatus  int
+		wantHeaders map[string]string
+	}{
+		{
+			name:       "route limit",
+			path:       "/score",
+			requests:   1,
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"RateLimit-Limit":     "2",
+				"RateLimit-Remaining": "1",
+				"RateLimit-Reset":     "30",
+				"RateLimit-Policy":    "2;w=60",
+				"Retry-After":         "",
+			},
+		},
+		{
+			name:       "route limit spent",
+			path:       "/score",
+			requests:   3,
+			wantStatus: http.StatusTooManyRequests,
+			wantHeaders: map[string]string{
+				"RateLimit-Limit":     "2",
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     "60",
+				"RateLimit-Policy":    "2;w=60",
+				"Retry-After":         "30",
+			},
+		},
+		{
+			name:       "default limit",
+			path:       "/other",
+			requests:   1,
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"RateLimit-Limit":     "10",
+				"RateLimit-Remaining": "9",
+				"RateLimit-Reset":     "1",
+				"RateLimit-Policy":    "10;w=1",
+			},
+		},
+		{
+			name:       "api key quota is closer to running out",
+			path:       "/reports",
+			keyQuota:   3,
+			requests:   1,
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"RateLimit-Limit":     "3",
+				"RateLimit-Remaining": "2",
+				"RateLimit-Reset":     "20",
+				"RateLimit-Policy":    "3;w=60",
+			},
+		},
+		{
+			name:       "api key quota spent",
+			path:       "/reports",
+			keyQuota:   3,
+			requests:   4,
+			wantStatus: http.StatusTooManyRequests,
+			wantHeaders: map[string]string{
+				"RateLimit-Limit":     "3",
+				"RateLimit-Remaining": "0",
+				"RateLimit-Policy":    "3;w=60",
+				"Retry-After":         "20",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(Errors(zap.NewNop()))
+			router.Use(func(c *gin.Context) {
+				c.Set("principal", authz.Principal{UserID: "us_1", TenantID: "acme", RateLimit: tt.keyQuota})
+			})
+			router.Use(RateLimit(ratelimit.New(nil, zap.NewNop()), policies))
+			for _, path := range []string{"/score", "/reports", "/other"} {
+				router.GET(path, func(c *gin.Context) { c.Status(http.StatusOK) })
+			}
+
+			var w *httptest.ResponseRecorder
+			for i := 0; i < tt.requests; i++ {
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			for header, want := range tt.wantHeaders {
+				if got := w.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+		})
+	}
+}
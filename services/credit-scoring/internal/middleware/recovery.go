@@ -1,7 +1,7 @@
 package middleware
 
 import (
-	"net/http"
+	"fmt"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -19,10 +19,7 @@ func Recovery(logger *zap.Logger) gin.HandlerFunc {
 					zap.String("method", c.Request.Method),
 				)
 
-				c.JSON(http.StatusInternalServerError, errors.NewAPIError(
-					"INTERNAL_ERROR",
-					"An unexpected error occurred",
-				))
+				writeProblem(c, logger, errors.Internal("An unexpected error occurred", fmt.Errorf("panic: %v", err)))
 				c.Abort()
 			}
 		}()
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"credit-scoring/pkg/errors"
+	"credit-scoring/pkg/jwks"
+)
+
+// asymmetricMethods are the signing algorithms verified against the key set
+var asymmetricMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+type TokenVerifierConfig struct {
+	// Secret verifies HS256 tokens; empty rejects them
+	Secret string
+	// Keys verify asymmetrically signed tokens by kid; nil rejects them
+	Keys *jwks.Set
+	// Issuer and Audience must match the iss and aud claims when set
+	Issuer   string
+	Audience string
+	// Leeway is the clock skew tolerated when checking exp, nbf and iat
+	Leeway time.Duration
+	// Revocations, if set, rejects logged out tokens
+	Revocations RevocationList
+}
+
+// RevocationList reports whether a token, by its jti claim, or the session it
+// belongs to, by its sid claim, has been revoked.
+type RevocationList interface {
+	IsRevoked(ctx context.Context, jti, sid string) (bool, error)
+}
+
+// TokenVerifier validates bearer JWTs for the HTTP and gRPC servers. Every
+// token must carry exp; nbf and iat are checked when present.
+type TokenVerifier struct {
+	secret      []byte
+	keys        *jwks.Set
+	parser      *jwt.Parser
+	revocations RevocationList
+}
+
+func NewTokenVerifier(cfg TokenVerifierConfig) *TokenVerifier {
+	var methods []string
+	if cfg.Secret != "" {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.Keys != nil {
+		methods = append(methods, asymmetricMethods...)
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	return &TokenVerifier{
+		secret:      []byte(cfg.Secret),
+		keys:        cfg.Keys,
+		parser:      jwt.NewParser(opts...),
+		revocations: cfg.Revocations,
+	}
+}
+
+// ParseBearerToken validates an "Authorization: Bearer <jwt>" value and returns
+// the token's claims.
+func (v *TokenVerifier) ParseBearerToken(ctx context.Context, authHeader string) (jwt.MapClaims, error) {
+	if authHeader == "" {
+		return nil, errors.Unauthorized("Missing authorization header")
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, errors.Unauthorized("Invalid authorization format")
+	}
+
+	claims := jwt.MapClaims{}
+	token, err := v.parser.ParseWithClaims(parts[1], claims, v.keyFunc(ctx))
+	if err != nil || !token.Valid {
+		return nil, errors.Unauthorized("Invalid or expired token")
+	}
+
+	if v.revocations != nil {
+		jti, _ := claims["jti"].(string)
+		sid, _ := claims["sid"].(string)
+		revoked, err := v.revocations.IsRevoked(ctx, jti, sid)
+		if err != nil {
+			return nil, errors.Unavailable("Unable to check whether the token was revoked", err)
+		}
+		if revoked {
+			return nil, errors.Unauthorized("Token has been revoked")
+		}
+	}
+
+	return claims, nil
+}
+
+// keyFunc picks the verification key. HMAC tokens only verify against the
+// secret and everything else only against the key set, so a public key can
+// never be used as an HMAC secret.
+func (v *TokenVerifier) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+			if len(v.secret) == 0 {
+				return nil, jwt.ErrTokenUnverifiable
+			}
+			return v.secret, nil
+		}
+		if v.keys == nil {
+			return nil, jwt.ErrTokenUnverifiable
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		key, err := v.keys.Key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if key.Algorithm != "" && key.Algorithm != token.Method.Alg() {
+			return nil, fmt.Errorf("key %q is restricted to %s", kid, key.Algorithm)
+		}
+		return key.Public, nil
+	}
+}
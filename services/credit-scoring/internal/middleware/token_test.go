@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"credit-scoring/pkg/errors"
+)
+
+type fakeRevocationList struct {
+	tokens   map[string]bool
+	sessions map[string]bool
+	err      error
+}
+
+func (f *fakeRevocationList) IsRevoked(ctx context.Context, jti, sid string) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.tokens[jti] || f.sessions[sid], nil
+}
+
+func TestTokenVerifierParseBearerToken(t *testing.T) {
+	const secret = "test-secret"
+
+	sign := func(method jwt.SigningMethod, key interface{}, edit func(jwt.MapClaims)) string {
+		now := time.Now()
+		claims := jwt.MapClaims{
+			"sub": "us_1",
+			"jti": "jti-1",
+			"sid": "ss_1",
+			"iss": "credit-scoring",
+			"aud": "api",
+			"iat": now.Unix(),
+			"exp": now.Add(time.Minute).Unix(),
+		}
+		if edit != nil {
+			edit(claims)
+		}
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + token
+	}
+	hs256 := func(edit func(jwt.MapClaims)) string {
+		return sign(jwt.SigningMethodHS256, []byte(secret), edit)
+	}
+
+	tests := []struct {
+		name        string
+		header      string
+		revocations *fakeRevocationList
+		wantStatus  int
+	}{
+		{name: "valid", header: hs256(nil)},
+		{name: "missing header", header: "", wantStatus: http.StatusUnauthorized},
+		{name: "not bearer", header: "Basic dXM6cHc=", wantStatus: http.StatusUnauthorized},
+		{
+			name:       "expired",
+			header:     hs256(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "no expiry",
+			header:     hs256(func(c jwt.MapClaims) { delete(c, "exp") }),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong secret",
+			header:     sign(jwt.SigningMethodHS256, []byte("other-secret"), nil),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "unsigned",
+			header:     sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, nil),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "other algorithm",
+			header:     sign(jwt.SigningMethodHS512, []byte(secret), nil),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong issuer",
+			header:     hs256(func(c jwt.MapClaims) { c["iss"] = "someone-else" }),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong audience",
+			header:     hs256(func(c jwt.MapClaims) { c["aud"] = "other" }),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "revoked token",
+			header:      hs256(nil),
+			revocations: &fakeRevocationList{tokens: map[string]bool{"jti-1": true}},
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			name:        "revoked session",
+			header:      hs256(nil),
+			revocations: &fakeRevocationList{sessions: map[string]bool{"ss_1": true}},
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			name:        "revocation list unavailable",
+			header:      hs256(nil),
+			revocations: &fakeRevocationList{err: stderrors.New("connection refused")},
+			wantStatus:  http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := TokenVerifierConfig{Secret: secret, Issuer: "credit-scoring", Audience: "api"}
+			if tt.revocations != nil {
+				cfg.Revocations = tt.revocations
+			}
+
+			claims, err := NewTokenVerifier(cfg).ParseBearerToken(context.Background(), tt.header)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("ParseBearerToken: %v", err)
+				}
+				if claims["sub"] != "us_1" {
+					t.Errorf("sub = %v, want us_1", claims["sub"])
+				}
+				return
+			}
+
+			e, ok := errors.As(err)
+			if !ok || e.Status() != tt.wantStatus {
+				t.Fatalf("error = %v, want status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"credit-scoring/internal/authz"
+	"credit-scoring/internal/model"
+	"credit-scoring/pkg/errors"
+)
+
+// UsageMeter counts requests for usage reports and billing.
+type UsageMeter interface {
+	// Record counts a successful request; billable ones also count towards
+	// the tenant's monthly scoring quota
+	Record(ctx context.Context, tenantID, callerID, endpoint string, billable bool)
+	ScoreQuota(ctx context.Context, tenantID string) (model.UsageQuota, error)
+}
+
+// Meter records every successful request against the caller's tenant, the
+// caller and the route. Replays of idempotent requests did no new work and
+// aren't counted.
+func Meter(meter UsageMeter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		// Errors are written by the Errors middleware once this returns
+		failed := len(c.Errors) > 0 || c.Writer.Status() >= 400
+		if failed || c.Writer.Header().Get("Idempotent-Replayed") != "" {
+			return
+		}
+		value, ok := c.Get("principal")
+		if !ok {
+			return
+		}
+
+		principal := value.(authz.Principal)
+		meter.Record(c.Request.Context(), principal.TenantID, principal.UserID,
+			c.Request.Method+" "+c.FullPath(), c.GetBool("billable"))
+	}
+}
+
+// ScoreQuota rejects a scoring request once the caller's tenant has used its
+// monthly scoring quota, with Retry-After set to the start of the next month.
+// Requests it lets through are counted as billable by Meter.
+func ScoreQuota(meter UsageMeter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := c.MustGet("principal").(authz.Principal)
+
+		if err := checkScoreQuota(c.Request.Context(), meter, principal.TenantID, func(retryAfter time.Duration) {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+		}); err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.Set("billable", true)
+		c.Next()
+	}
+}
+
+// checkScoreQuota returns a RateLimited error if tenantID has used its
+// monthly scoring quota, after passing retryAfter how long until it resets.
+func checkScoreQuota(ctx context.Context, meter UsageMeter, tenantID string, retryAfter func(time.Duration)) error {
+	quota, err := meter.ScoreQuota(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if quota.Exceeded() {
+		retryAfter(time.Until(quota.ResetsAt))
+		return errors.RateLimited("Monthly scoring quota exceeded").WithCode("QUOTA_EXCEEDED")
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/authz"
+	"credit-scoring/internal/model"
+	"credit-scoring/pkg/errors"
+)
+
+type usageRecord struct {
+	tenantID, callerID, endpoint string
+	billable                     bool
+}
+
+type fakeUsageMeter struct {
+	mu      sync.Mutex
+	quota   model.UsageQuota
+	err     error
+	records []usageRecord
+}
+
+func (m *fakeUsageMeter) Record(ctx context.Context, tenantID, callerID, endpoint string, billable bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, usageRecord{tenantID, callerID, endpoint, billable})
+}
+
+func (m *fakeUsageMeter) ScoreQuota(ctx context.Context, tenantID string) (model.UsageQuota, error) {
+	return m.quota, m.err
+}
+
+func TestScoreQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	resetsAt := time.Now().Add(90 * time.Minute)
+
+	tests := []struct {
+		name       string
+		quota      model.UsageQuota
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "within quota",
+			quota:      model.UsageQuota{Limit: 100, Used: 99, ResetsAt: resetsAt},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "no quota",
+			quota:      model.UsageQuota{Used: 1_000_000, ResetsAt: resetsAt},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "quota used up",
+			quota:      model.UsageQuota{Limit: 100, Used: 100, ResetsAt: resetsAt},
+			wantStatus: http.StatusTooManyRequests,
+			wantCode:   "QUOTA_EXCEEDED",
+		},
+		{
+			name:       "quota unavailable",
+			err:        errors.Unavailable("Usage is unavailable", context.DeadlineExceeded),
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   "SERVICE_UNAVAILABLE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meter := &fakeUsageMeter{quota: tt.quota, err: tt.err}
+			ran := false
+
+			router := gin.New()
+			router.Use(Errors(zap.NewNop()))
+			router.POST("/score",
+				func(c *gin.Context) {
+					c.Set("principal", authz.Principal{UserID: "u1", TenantID: "acme"})
+				},
+				Meter(meter),
+				ScoreQuota(meter),
+				func(c *gin.Context) {
+					ran = true
+					c.JSON(http.StatusCreated, gin.H{"score": 700})
+				},
+			)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/score", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if tt.wantCode == "" {
+				if !ran {
+					t.Error("handler did not run")
+				}
+				want := usageRecord{"acme", "u1", "POST /score", true}
+				if len(meter.records) != 1 || meter.records[0] != want {
+					t.Errorf("recorded %+v, want one billable request %+v", meter.records, want)
+				}
+				return
+			}
+
+			if ran {
+				t.Error("handler ran")
+			}
+			if len(meter.records) != 0 {
+				t.Errorf("refused request was recorded: %+v", meter.records)
+			}
+			var problem errors.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil || problem.Code != tt.wantCode {
+				t.Errorf("body = %s, want code %s", w.Body, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestScoreQuotaRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	meter := &fakeUsageMeter{quota: model.UsageQuota{Limit: 10, Used: 12, ResetsAt: time.Now().Add(90 * time.Minute)}}
+	router := gin.New()
+	router.Use(Errors(zap.NewNop()))
+	router.POST("/score",
+		func(c *gin.Context) { c.Set("principal", authz.Principal{UserID: "u1", TenantID: "acme"}) },
+		ScoreQuota(meter),
+		func(c *gin.Context) { t.Error("handler ran") },
+	)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/score", nil))
+
+	// Rounded up to whole seconds until the quota resets
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 90*60-5 || retryAfter > 90*60 {
+		t.Errorf("Retry-After = %q, want about %d", w.Header().Get("Retry-After"), 90*60)
+	}
+}
+
+func TestMeterSkipsUncountedRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		want    int
+	}{
+		{
+			name:    "success is counted, not billable",
+			handler: func(c *gin.Context) { c.Status(http.StatusOK) },
+			want:    1,
+		},
+		{
+			name:    "error",
+			handler: func(c *gin.Context) { c.Error(errors.NotFound("Credit score not found")) },
+		},
+		{
+			name: "idempotent replay",
+			handler: func(c *gin.Context) {
+				c.Header("Idempotent-Replayed", "true")
+				c.Status(http.StatusCreated)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meter := &fakeUsageMeter{}
+			router := gin.New()
+			router.Use(Errors(zap.NewNop()))
+			router.GET("/score",
+				func(c *gin.Context) { c.Set("principal", authz.Principal{UserID: "u1", TenantID: "acme"}) },
+				Meter(meter),
+				tt.handler,
+			)
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/score", nil))
+
+			if len(meter.records) != tt.want {
+				t.Fatalf("recorded %d requests, want %d", len(meter.records), tt.want)
+			}
+			if tt.want > 0 && meter.records[0].billable {
+				t.Error("request outside ScoreQuota recorded as billable")
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"credit-scoring/pkg/errors"
+)
+
+// APIVersionHeader carries the API version a client asks for and the one
+// that served the response.
+const APIVersionHeader = "API-Version"
+
+// versionMediaType matches Accept values like
+// application/vnd.credit-scoring.v2+json
+var versionMediaType = regexp.MustCompile(`application/vnd\.credit-scoring\.v(\d+)\+json`)
+
+// APIVersion tags responses with the version of the API group serving them.
+// A client may also ask for a version with the API-Version header or an
+// application/vnd.credit-scoring.v<N>+json Accept type; asking for a version
+// other than the one at the request path is rejected with 406 so the client
+// doesn't silently get a representation it can't parse.
+func APIVersion(version int) gin.HandlerFunc {
+	served := strconv.Itoa(version)
+
+	return func(c *gin.Context) {
+		c.Header(APIVersionHeader, served)
+
+		if requested := requestedVersion(c.Request); requested != "" && requested != served {
+			abortWithError(c, errors.NotAcceptable(
+				fmt.Sprintf("API version %q was requested but this endpoint serves version %s", requested, served),
+			).WithCode("API_VERSION_MISMATCH"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// requestedVersion returns the version asked for by the request, or "" if it
+// doesn't ask for one. The API-Version header takes precedence over Accept.
+func requestedVersion(r *http.Request) string {
+	if v := strings.TrimSpace(r.Header.Get(APIVersionHeader)); v != "" {
+		return strings.TrimPrefix(v, "v")
+	}
+
+	if m := versionMediaType.FindStringSubmatch(r.Header.Get("Accept")); m != nil {
+		return m[1]
+	}
+
+	return ""
+}
+
+// Deprecated marks responses of a deprecated endpoint with a Deprecation
+// header (RFC 9745), a Sunset header (RFC 8594) and a successor-version link
+// to the same path with prefix replaced by successorPrefix. Zero dates are
+// left out.
+func Deprecated(deprecation, sunset time.Time, prefix, successorPrefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !deprecation.IsZero() {
+			c.Header("Deprecation", fmt.Sprintf("@%d", deprecation.Unix()))
+		}
+		if !sunset.IsZero() {
+			c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		if path, ok := strings.CutPrefix(c.Request.URL.Path, prefix); ok {
+			c.Header("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, successorPrefix, path))
+		}
+
+		c.Next()
+	}
+}
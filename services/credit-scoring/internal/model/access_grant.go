@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// AccessGrant lets GranteeID act on OwnerID's credit data with the listed
+// permissions until it expires or is revoked.
+type AccessGrant struct {
+	ID          string     `db:"id"`
+	OwnerID     string     `db:"owner_id"`
+	GranteeID   string     `db:"grantee_id"`
+	Permissions []string   `db:"permissions"`
+	ExpiresAt   *time.Time `db:"expires_at"`
+	RevokedAt   *time.Time `db:"revoked_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
+
+// AccessAuditEntry records an attempt to act on another user's credit data.
+type AccessAuditEntry struct {
+	ID            int64     `db:"id"`
+	ActorID       string    `db:"actor_id"`
+	ActorRole     string    `db:"actor_role"`
+	SubjectUserID string    `db:"subject_user_id"`
+	Permission    string    `db:"permission"`
+	Basis         *string   `db:"basis"`
+	GrantID       *string   `db:"grant_id"`
+	Allowed       bool      `db:"allowed"`
+	Action        string    `db:"action"`
+	RequestID     *string   `db:"request_id"`
+	CreatedAt     time.Time `db:"created_at"`
+}
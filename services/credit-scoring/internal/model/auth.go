@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+type User struct {
+	ID           string    `db:"id"`
+	TenantID     string    `db:"tenant_id"`
+	Email        string    `db:"email"`
+	PasswordHash string    `db:"password_hash"`
+	Role         string    `db:"role"`
+	Active       bool      `db:"active"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// APIClient is a backend integration that authenticates with a client ID and
+// secret.
+type APIClient struct {
+	ID         string    `db:"id"`
+	TenantID   string    `db:"tenant_id"`
+	Name       string    `db:"name"`
+	SecretHash string    `db:"secret_hash"`
+	Role       string    `db:"role"`
+	Active     bool      `db:"active"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// RefreshToken is one token in a login session. Using it marks it used and
+// issues the next token in the same session.
+type RefreshToken struct {
+	ID        string     `db:"id"`
+	SessionID string     `db:"session_id"`
+	UserID    string     `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// APIKey lets a partner call the API without tokens. It acts for its tenant,
+// limited to its scopes and to RateLimit requests per minute.
+type APIKey struct {
+	ID         string     `db:"id"`
+	Name       string     `db:"name"`
+	TenantID   string     `db:"tenant_id"`
+	KeyPrefix  string     `db:"key_prefix"`
+	KeyHash    string     `db:"key_hash"`
+	Scopes     []string   `db:"scopes"`
+	RateLimit  int        `db:"rate_limit"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	CreatedBy  string     `db:"created_by"`
+	CreatedAt  time.Time  `db:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+}
@@ -3,14 +3,25 @@ package model
 import "time"
 
 type CreditScore struct {
-	ID             string    `db:"id"`
-	UserID         string    `db:"user_id"`
-	Score          int       `db:"score"`
-	Grade          string    `db:"grade"`
-	Factors        []string  `db:"factors"`
-	Recommendation string    `db:"recommendation"`
-	CalculatedAt   time.Time `db:"calculated_at"`
-	ExpiresAt      time.Time `db:"expires_at"`
-	CreatedAt      time.Time `db:"created_at"`
-	UpdatedAt      time.Time `db:"updated_at"`
+	ID             string     `db:"id"`
+	TenantID       string     `db:"tenant_id"`
+	UserID         string     `db:"user_id"`
+	Score          int        `db:"score"`
+	Grade          string     `db:"grade"`
+	Factors        []string   `db:"factors"`
+	Recommendation string     `db:"recommendation"`
+	ModelVersion   string     `db:"model_version"`
+	SubScores      []SubScore `db:"sub_scores"`
+	ReasonCodes    []string   `db:"reason_codes"`
+	CalculatedAt   time.Time  `db:"calculated_at"`
+	ExpiresAt      time.Time  `db:"expires_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+}
+
+// SubScore is one weighted component of a credit score, stored as JSON.
+type SubScore struct {
+	Name   string  `json:"name"`
+	Score  int     `json:"score"`
+	Weight float64 `json:"weight"`
 }
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type CreditScoreInputs struct {
+	UserID           string          `db:"user_id"`
+	IncomeAmount     float64         `db:"income_amount"`
+	EmploymentStatus string          `db:"employment_status"`
+	AccountAge       int             `db:"account_age"`
+	TransactionData  json.RawMessage `db:"transaction_data"`
+	LoanHistory      json.RawMessage `db:"loan_history"`
+	CreatedAt        time.Time       `db:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// ExportWatermark records how far an incremental export job has got.
+type ExportWatermark struct {
+	Job           string    `db:"job"`
+	LastCreatedAt time.Time `db:"last_created_at"`
+	LastID        string    `db:"last_id"`
+	LastLocation  string    `db:"last_location"`
+	LastRowCount  int64     `db:"last_row_count"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
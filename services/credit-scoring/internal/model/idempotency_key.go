@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type IdempotencyKey struct {
+	Key          string    `db:"idempotency_key"`
+	Principal    string    `db:"principal"`
+	RequestHash  string    `db:"request_hash"`
+	StatusCode   int       `db:"status_code"`
+	ResponseBody []byte    `db:"response_body"`
+	ContentType  string    `db:"content_type"`
+	ExpiresAt    time.Time `db:"expires_at"`
+	CreatedAt    time.Time `db:"created_at"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Tenant is a lending partner served by the deployment. Tenants without a
+// row use the default tenant's settings.
+type Tenant struct {
+	ID   string `db:"id"`
+	Name string `db:"name"`
+	// ScoringModel is nil for tenants that use the built-in model
+	ScoringModel *ScoringModel `db:"scoring_model"`
+	// MonthlyScoreQuota is how many billable scoring requests the tenant may
+	// make per calendar month; zero for no limit
+	MonthlyScoreQuota int       `db:"monthly_score_quota"`
+	CreatedAt         time.Time `db:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at"`
+}
+
+// ScoringModel configures how a tenant's scores are calculated, stored as
+// JSON.
+type ScoringModel struct {
+	// Version is recorded on every score the model produces
+	Version string         `json:"version"`
+	Weights ScoringWeights `json:"weights"`
+	// ValidityDays is how long a score is valid before it expires
+	ValidityDays int `json:"validityDays"`
+}
+
+// ScoringWeights are the weights of the sub-scores in the total score. They
+// add up to 1.
+type ScoringWeights struct {
+	Income      float64 `json:"income"`
+	Employment  float64 `json:"employment"`
+	AccountAge  float64 `json:"accountAge"`
+	LoanHistory float64 `json:"loanHistory"`
+}
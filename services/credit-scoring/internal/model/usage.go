@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// UsageRecord counts a caller's successful requests to one endpoint in a day
+// or month.
+type UsageRecord struct {
+	TenantID string `db:"tenant_id"`
+	// Period is the day, or the first day of the month
+	Period   time.Time `db:"period"`
+	CallerID string    `db:"caller_id"`
+	// Endpoint is the route as "METHOD /path", or the gRPC method name
+	Endpoint string `db:"endpoint"`
+	// Billable requests count towards the tenant's monthly scoring quota
+	Billable     bool  `db:"billable"`
+	RequestCount int64 `db:"request_count"`
+}
+
+// UsageQuota is a tenant's use of its monthly scoring quota.
+type UsageQuota struct {
+	// Month is the first day of the month, in UTC
+	Month time.Time
+	// Limit is zero when the tenant has no quota
+	Limit int64
+	Used  int64
+	// ResetsAt is when the next month's quota starts
+	ResetsAt time.Time
+}
+
+// Exceeded reports whether no billable requests are left this month.
+func (q UsageQuota) Exceeded() bool {
+	return q.Limit > 0 && q.Used >= q.Limit
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+type WebhookSubscription struct {
+	ID         string    `db:"id"`
+	OwnerID    string    `db:"owner_id"`
+	URL        string    `db:"url"`
+	EventTypes []string  `db:"event_types"`
+	Secret     string    `db:"secret"`
+	Active     bool      `db:"active"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// WebhookDelivery is a notifications row with notification_type 'WEBHOOK'.
+type WebhookDelivery struct {
+	ID             string     `db:"id"`
+	SubscriptionID string     `db:"webhook_subscription_id"`
+	UserID         string     `db:"user_id"`
+	EventType      string     `db:"channel"`
+	URL            string     `db:"recipient"`
+	Payload        string     `db:"content"`
+	Status         string     `db:"status"`
+	RetryCount     int        `db:"retry_count"`
+	NextAttemptAt  *time.Time `db:"next_attempt_at"`
+	SentAt         *time.Time `db:"sent_at"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+	FailureReason  *string    `db:"failure_reason"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
+
+type WebhookDeliveryAttempt struct {
+	ID             int64     `db:"id"`
+	NotificationID string    `db:"notification_id"`
+	Attempt        int       `db:"attempt"`
+	StatusCode     *int      `db:"status_code"`
+	Error          *string   `db:"error"`
+	DurationMs     int       `db:"duration_ms"`
+	AttemptedAt    time.Time `db:"attempted_at"`
+}
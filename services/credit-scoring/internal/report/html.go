@@ -0,0 +1,94 @@
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"credit-scoring/internal/dto"
+)
+
+//go:embed report.html
+var htmlSource string
+
+// SVG chart size in user units; the chart scales to the page width
+const (
+	svgWidth   = 640.0
+	svgHeight  = 220.0
+	svgPadLeft = 40.0
+	svgPadTop  = 10.0
+)
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date":       func(t time.Time) string { return t.Format(dateFormat) },
+	"amount":     formatAmount,
+	"gradeColor": func(grade string) string { return gradeColor(grade).hex() },
+}).Parse(htmlSource))
+
+type htmlChartTick struct {
+	Score int
+	Y     float64
+}
+
+type htmlChart struct {
+	Width, Height float64
+	Left, Top     float64
+	PlotW, PlotH  float64
+	Polyline      string
+	Points        []point
+	Ticks         []htmlChartTick
+	FirstDate     string
+	LastDate      string
+}
+
+type htmlData struct {
+	*dto.CreditReport
+	Title      string
+	Brand      string
+	BrandColor string
+	MutedColor string
+	GridColor  string
+	Chart      *htmlChart
+}
+
+func renderHTML(w io.Writer, r *dto.CreditReport) error {
+	data := htmlData{
+		CreditReport: r,
+		Title:        title,
+		Brand:        brand,
+		BrandColor:   brandColor.hex(),
+		MutedColor:   mutedColor.hex(),
+		GridColor:    gridColor.hex(),
+	}
+
+	if len(r.History) > 0 {
+		chart := &htmlChart{
+			Width:  svgWidth,
+			Height: svgHeight,
+			Left:   svgPadLeft,
+			Top:    svgPadTop,
+			PlotW:  svgWidth - svgPadLeft - 10,
+			PlotH:  svgHeight - svgPadTop - 30,
+		}
+
+		chart.Points = chartPoints(r.History, chart.PlotW, chart.PlotH)
+		coords := make([]string, len(chart.Points))
+		for i, p := range chart.Points {
+			coords[i] = fmt.Sprintf("%.1f,%.1f", p.X, p.Y)
+		}
+		chart.Polyline = strings.Join(coords, " ")
+
+		for _, score := range chartTicks {
+			chart.Ticks = append(chart.Ticks, htmlChartTick{Score: score, Y: scoreY(score, chart.PlotH)})
+		}
+
+		chart.FirstDate = r.History[0].CalculatedAt.Format(dateFormat)
+		chart.LastDate = r.History[len(r.History)-1].CalculatedAt.Format(dateFormat)
+		data.Chart = chart
+	}
+
+	return htmlTemplate.Execute(w, data)
+}
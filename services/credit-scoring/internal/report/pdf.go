@@ -0,0 +1,245 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"credit-scoring/internal/dto"
+)
+
+// Page layout in millimetres (A4 portrait)
+const (
+	pageW       = 210.0
+	pageH       = 297.0
+	pageMargin  = 15.0
+	footerH     = 5.0
+	contentW    = pageW - 2*pageMargin
+	headerH     = 28.0
+	chartH      = 55.0
+	chartAxisW  = 12.0
+	lineH       = 5.5
+	tableHeadH  = 7.0
+	tableLineH  = 6.5
+	sectionGapH = 6.0
+)
+
+// tradelineColumns are the tradeline table's headings and widths, summing to
+// contentW
+var tradelineColumns = []struct {
+	heading string
+	width   float64
+	align   string
+}{
+	{"Creditor", 34, "L"},
+	{"Type", 22, "L"},
+	{"Account", 24, "L"},
+	{"Status", 18, "L"},
+	{"Balance", 24, "R"},
+	{"Limit", 24, "R"},
+	{"Payment", 15, "L"},
+	{"Opened", 19, "L"},
+}
+
+func renderPDF(w io.Writer, r *dto.CreditReport) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(title+" - "+r.UserID, true)
+	pdf.SetAuthor(brand, true)
+	pdf.SetCreationDate(r.GeneratedAt)
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin+footerH)
+
+	// Core fonts are Latin-1; translate UTF-8 input so names render
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pageMargin)
+		setColor(pdf.SetTextColor, mutedColor)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(contentW/2, footerH, tr(brand+" - generated "+r.GeneratedAt.Format(dateFormat)), "", 0, "L", false, 0, "")
+		pdf.CellFormat(contentW/2, footerH, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	pdf.AliasNbPages("")
+	pdf.AddPage()
+
+	pdfHeader(pdf, tr, r)
+	if r.Score != nil {
+		pdfScore(pdf, tr, r.Score)
+	}
+	pdfHistory(pdf, r.History)
+	pdfTradelines(pdf, tr, r)
+
+	return pdf.Output(w)
+}
+
+func setColor(set func(r, g, b int), c rgb) {
+	set(c.R, c.G, c.B)
+}
+
+func pdfHeader(pdf *gofpdf.Fpdf, tr func(string) string, r *dto.CreditReport) {
+	setColor(pdf.SetFillColor, brandColor)
+	pdf.Rect(0, 0, pageW, headerH, "F")
+
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetXY(pageMargin, 8)
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.CellFormat(contentW, 8, title, "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(contentW, 6, tr(fmt.Sprintf("%s - User %s - Generated %s", brand, r.UserID, r.GeneratedAt.Format(dateFormat))), "", 1, "L", false, 0, "")
+
+	pdf.SetY(headerH + sectionGapH)
+}
+
+func pdfSection(pdf *gofpdf.Fpdf, heading string) {
+	setColor(pdf.SetTextColor, brandColor)
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(contentW, 7, heading, "", 1, "L", false, 0, "")
+
+	setColor(pdf.SetDrawColor, gridColor)
+	pdf.Line(pageMargin, pdf.GetY(), pageMargin+contentW, pdf.GetY())
+	pdf.Ln(2)
+
+	pdf.SetTextColor(34, 34, 34)
+	pdf.SetFont("Helvetica", "", 10)
+}
+
+func pdfScore(pdf *gofpdf.Fpdf, tr func(string) string, s *dto.CreditScore) {
+	pdfSection(pdf, "Current Score")
+
+	y := pdf.GetY()
+	setColor(pdf.SetTextColor, gradeColor(s.Grade))
+	pdf.SetFont("Helvetica", "B", 36)
+	pdf.CellFormat(35, 16, strconv.Itoa(s.Score), "", 0, "L", false, 0, "")
+
+	pdf.SetXY(pageMargin+35, y+2)
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(contentW-35, 7, s.Grade, "", 2, "L", false, 0, "")
+	setColor(pdf.SetTextColor, mutedColor)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(contentW-35, 5, fmt.Sprintf("Calculated %s - Valid until %s",
+		s.CalculatedAt.Format(dateFormat), s.ExpiresAt.Format(dateFormat)), "", 1, "L", false, 0, "")
+	pdf.SetY(y + 16 + sectionGapH)
+
+	pdfSection(pdf, "Key Factors")
+	if len(s.Factors) == 0 {
+		setColor(pdf.SetTextColor, mutedColor)
+		pdf.CellFormat(contentW, lineH, "No factors recorded.", "", 1, "L", false, 0, "")
+	}
+	for _, factor := range s.Factors {
+		pdf.CellFormat(5, lineH, "-", "", 0, "L", false, 0, "")
+		pdf.MultiCell(contentW-5, lineH, tr(factor), "", "L", false)
+	}
+	pdf.Ln(sectionGapH)
+
+	pdfSection(pdf, "Recommendation")
+	pdf.MultiCell(contentW, lineH, tr(s.Recommendation), "", "L", false)
+	pdf.Ln(sectionGapH)
+}
+
+func pdfHistory(pdf *gofpdf.Fpdf, history []dto.CreditScore) {
+	// Keep the chart and its heading on one page
+	if pdf.GetY()+chartH+25 > pageH-pageMargin-footerH {
+		pdf.AddPage()
+	}
+	pdfSection(pdf, "Score History")
+
+	if len(history) == 0 {
+		setColor(pdf.SetTextColor, mutedColor)
+		pdf.CellFormat(contentW, lineH, "No score history.", "", 1, "L", false, 0, "")
+		pdf.Ln(sectionGapH)
+		return
+	}
+
+	left := pageMargin + chartAxisW
+	top := pdf.GetY() + 3
+	width := contentW - chartAxisW - 3
+
+	pdf.SetFont("Helvetica", "", 7)
+	setColor(pdf.SetTextColor, mutedColor)
+	setColor(pdf.SetDrawColor, gridColor)
+	pdf.SetLineWidth(0.2)
+	for _, score := range chartTicks {
+		y := top + scoreY(score, chartH)
+		pdf.Line(left, y, left+width, y)
+		pdf.SetXY(pageMargin, y-2)
+		pdf.CellFormat(chartAxisW-2, 4, strconv.Itoa(score), "", 0, "R", false, 0, "")
+	}
+
+	points := chartPoints(history, width, chartH)
+	setColor(pdf.SetDrawColor, brandColor)
+	setColor(pdf.SetFillColor, brandColor)
+	pdf.SetLineWidth(0.6)
+	for i := 1; i < len(points); i++ {
+		pdf.Line(left+points[i-1].X, top+points[i-1].Y, left+points[i].X, top+points[i].Y)
+	}
+	for _, p := range points {
+		pdf.Circle(left+p.X, top+p.Y, 0.9, "F")
+	}
+	pdf.SetLineWidth(0.2)
+
+	pdf.SetXY(left, top+chartH+1)
+	pdf.CellFormat(width/2, 4, history[0].CalculatedAt.Format(dateFormat), "", 0, "L", false, 0, "")
+	pdf.CellFormat(width/2, 4, history[len(history)-1].CalculatedAt.Format(dateFormat), "", 1, "R", false, 0, "")
+	pdf.Ln(sectionGapH)
+}
+
+func pdfTradelines(pdf *gofpdf.Fpdf, tr func(string) string, r *dto.CreditReport) {
+	pdfSection(pdf, "Bureau Tradelines")
+
+	if len(r.Tradelines) > 0 {
+		tableHeader := func() {
+			setColor(pdf.SetFillColor, brandColor)
+			pdf.SetTextColor(255, 255, 255)
+			pdf.SetFont("Helvetica", "B", 8)
+			for _, col := range tradelineColumns {
+				pdf.CellFormat(col.width, tableHeadH, col.heading, "", 0, col.align, true, 0, "")
+			}
+			pdf.Ln(-1)
+			pdf.SetTextColor(34, 34, 34)
+			pdf.SetFont("Helvetica", "", 8)
+		}
+		tableHeader()
+
+		setColor(pdf.SetDrawColor, gridColor)
+		for _, t := range r.Tradelines {
+			if pdf.GetY()+tableLineH > pageH-pageMargin-footerH {
+				pdf.AddPage()
+				tableHeader()
+			}
+			cells := []string{
+				t.Creditor,
+				t.AccountType,
+				t.AccountNumber,
+				t.Status,
+				formatAmount(t.Balance),
+				formatAmount(t.CreditLimit),
+				t.PaymentStatus,
+				t.OpenedAt.Format(dateFormat),
+			}
+			for i, col := range tradelineColumns {
+				pdf.CellFormat(col.width, tableLineH, fitText(pdf, tr(cells[i]), col.width-2), "B", 0, col.align, false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+		pdf.Ln(2)
+	}
+
+	if r.TradelinesNote != "" {
+		setColor(pdf.SetTextColor, mutedColor)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.MultiCell(contentW, lineH, r.TradelinesNote, "", "L", false)
+	}
+}
+
+// fitText truncates s with an ellipsis so it fits in width at the current font
+func fitText(pdf *gofpdf.Fpdf, s string, width float64) string {
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+	for len(s) > 0 && pdf.GetStringWidth(s+"...") > width {
+		s = s[:len(s)-1]
+	}
+	return s + "..."
+}
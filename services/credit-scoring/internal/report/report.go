@@ -0,0 +1,108 @@
+// Package report renders downloadable credit reports as HTML and PDF.
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"credit-scoring/internal/dto"
+)
+
+const (
+	title = "Credit Report"
+	brand = "Credit Scoring & Risk Assessment Platform"
+
+	// Score range shown on the history chart
+	minScore = 300
+	maxScore = 850
+
+	dateFormat = "02 Jan 2006"
+)
+
+// Render writes r in the given dto.ReportFormat* format and returns the
+// response content type.
+func Render(w io.Writer, format string, r *dto.CreditReport) (string, error) {
+	switch format {
+	case dto.ReportFormatHTML:
+		return "text/html; charset=utf-8", renderHTML(w, r)
+	case dto.ReportFormatPDF:
+		return "application/pdf", renderPDF(w, r)
+	default:
+		return "", fmt.Errorf("unsupported report format %q", format)
+	}
+}
+
+// rgb is a brand colour
+type rgb struct{ R, G, B int }
+
+func (c rgb) hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+var (
+	brandColor = rgb{31, 58, 147}
+	mutedColor = rgb{110, 117, 130}
+	gridColor  = rgb{222, 226, 232}
+)
+
+// gradeColors match the grade bands of the scoring model
+var gradeColors = map[string]rgb{
+	"Excellent": {25, 135, 84},
+	"Very Good": {60, 160, 90},
+	"Good":      {32, 121, 199},
+	"Fair":      {230, 150, 30},
+	"Poor":      {200, 50, 50},
+}
+
+func gradeColor(grade string) rgb {
+	if c, ok := gradeColors[grade]; ok {
+		return c
+	}
+	return mutedColor
+}
+
+// point is a position on the history chart, with Y growing downwards
+type point struct{ X, Y float64 }
+
+// chartPoints lays history out evenly across a width×height box, scaled to
+// the full score range. A single score is centred.
+func chartPoints(history []dto.CreditScore, width, height float64) []point {
+	points := make([]point, len(history))
+	for i, h := range history {
+		x := width / 2
+		if len(history) > 1 {
+			x = width * float64(i) / float64(len(history)-1)
+		}
+		points[i] = point{X: x, Y: scoreY(h.Score, height)}
+	}
+	return points
+}
+
+// chartTicks are the grade boundaries marked on the chart's Y axis
+var chartTicks = []int{300, 580, 670, 740, 800, 850}
+
+// scoreY is the distance of score from the top of a chart of the given height
+func scoreY(score int, height float64) float64 {
+	return height * (1 - float64(score-minScore)/float64(maxScore-minScore))
+}
+
+// formatAmount renders an amount with thousands separators and two decimals
+func formatAmount(amount float64) string {
+	s := strconv.FormatFloat(amount, 'f', 2, 64)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	var b strings.Builder
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return sign + b.String() + "." + frac
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"credit-scoring/internal/model"
+)
+
+// AccessRepository stores grants and the access audit log of the tenant in
+// the context.
+type AccessRepository struct {
+	db *sql.DB
+}
+
+func NewAccessRepository(db *sql.DB) *AccessRepository {
+	return &AccessRepository{db: db}
+}
+
+func (r *AccessRepository) CreateGrant(ctx context.Context, grant *model.AccessGrant) error {
+	query := `
+		INSERT INTO access_grants (id, tenant_id, owner_id, grantee_id, permissions, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`
+	return inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		return q.QueryRowContext(ctx, query,
+			grant.ID,
+			tenantID,
+			grant.OwnerID,
+			grant.GranteeID,
+			pq.Array(grant.Permissions),
+			grant.ExpiresAt,
+		).Scan(&grant.CreatedAt)
+	})
+}
+
+const grantColumns = `id, owner_id, grantee_id, permissions, expires_at, revoked_at, created_at`
+
+// ListGrants returns the grants ownerID has made, including revoked and
+// expired ones.
+func (r *AccessRepository) ListGrants(ctx context.Context, ownerID string) ([]*model.AccessGrant, error) {
+	query := `SELECT ` + grantColumns + ` FROM access_grants WHERE tenant_id = $1 AND owner_id = $2 ORDER BY created_at DESC`
+
+	var grants []*model.AccessGrant
+	err := inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		rows, err := q.QueryContext(ctx, query, tenantID, ownerID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			grant, err := scanGrant(rows)
+			if err != nil {
+				return err
+			}
+			grants = append(grants, grant)
+		}
+		return rows.Err()
+	})
+
+	return grants, err
+}
+
+// FindActiveGrant returns a grant from ownerID to granteeID that includes
+// permission and has neither expired nor been revoked.
+func (r *AccessRepository) FindActiveGrant(ctx context.Context, ownerID, granteeID, permission string) (*model.AccessGrant, error) {
+	query := `
+		SELECT ` + grantColumns + ` FROM access_grants
+		WHERE tenant_id = $1 AND owner_id = $2 AND grantee_id = $3 AND $4 = ANY(permissions)
+			AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var grant *model.AccessGrant
+	err := inTenant(ctx, r.db, func(q querier, tenantID string) (err error) {
+		grant, err = scanGrant(q.QueryRowContext(ctx, query, tenantID, ownerID, granteeID, permission))
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return grant, err
+}
+
+// RevokeGrant revokes the grant only if ownerID made it and it is not
+// already revoked.
+func (r *AccessRepository) RevokeGrant(ctx context.Context, ownerID, id string) error {
+	return inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		result, err := q.ExecContext(ctx,
+			`UPDATE access_grants SET revoked_at = NOW() WHERE id = $1 AND tenant_id = $2 AND owner_id = $3 AND revoked_at IS NULL`,
+			id, tenantID, ownerID,
+		)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err == nil && n == 0 {
+			return ErrNotFound
+		}
+		return err
+	})
+}
+
+func scanGrant(row rowScanner) (*model.AccessGrant, error) {
+	grant := &model.AccessGrant{}
+	var permissions pq.StringArray
+
+	err := row.Scan(
+		&grant.ID,
+		&grant.OwnerID,
+		&grant.GranteeID,
+		&permissions,
+		&grant.ExpiresAt,
+		&grant.RevokedAt,
+		&grant.CreatedAt,
+	)
+	grant.Permissions = []string(permissions)
+
+	return grant, err
+}
+
+func (r *AccessRepository) RecordAccess(ctx context.Context, entry *model.AccessAuditEntry) error {
+	query := `
+		INSERT INTO access_audit_log (
+			tenant_id, actor_id, actor_role, subject_user_id, permission, basis, grant_id, allowed, action, request_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at
+	`
+	return inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		return q.QueryRowContext(ctx, query,
+			tenantID,
+			entry.ActorID,
+			entry.ActorRole,
+			entry.SubjectUserID,
+			entry.Permission,
+			entry.Basis,
+			entry.GrantID,
+			entry.Allowed,
+			entry.Action,
+			entry.RequestID,
+		).Scan(&entry.ID, &entry.CreatedAt)
+	})
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"credit-scoring/internal/model"
+)
+
+// APIKeyRepository stores partner API keys. Keys are looked up before the
+// caller's tenant is known and are managed by administrators for any tenant,
+// so every query runs across tenants.
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) CreateKey(ctx context.Context, key *model.APIKey) error {
+	query := `
+		INSERT INTO api_keys (id, name, tenant_id, key_prefix, key_hash, scopes, rate_limit, expires_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at, updated_at
+	`
+	return inAllTenants(ctx, r.db, func(q querier) error {
+		return q.QueryRowContext(ctx, query,
+			key.ID,
+			key.Name,
+			key.TenantID,
+			key.KeyPrefix,
+			key.KeyHash,
+			pq.Array(key.Scopes),
+			key.RateLimit,
+			key.ExpiresAt,
+			key.CreatedBy,
+		).Scan(&key.CreatedAt, &key.UpdatedAt)
+	})
+}
+
+const apiKeyColumns = `id, name, tenant_id, key_prefix, key_hash, scopes, rate_limit, expires_at, last_used_at, revoked_at, created_by, created_at, updated_at`
+
+// GetKeyByHash returns the key whatever its state; the caller checks whether
+// it has expired or been revoked.
+func (r *APIKeyRepository) GetKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+
+	var key *model.APIKey
+	err := inAllTenants(ctx, r.db, func(q querier) (err error) {
+		key, err = scanAPIKey(q.QueryRowContext(ctx, query, hash))
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return key, err
+}
+
+// ListKeys returns the keys of tenantID, or of every tenant if it is empty,
+// including revoked and expired ones.
+func (r *APIKeyRepository) ListKeys(ctx context.Context, tenantID string) ([]*model.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + ` FROM api_keys
+		WHERE $1 = '' OR tenant_id = $1
+		ORDER BY created_at DESC
+	`
+
+	var keys []*model.APIKey
+	err := inAllTenants(ctx, r.db, func(q querier) error {
+		rows, err := q.QueryContext(ctx, query, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			key, err := scanAPIKey(rows)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, key)
+		}
+		return rows.Err()
+	})
+
+	return keys, err
+}
+
+// RevokeKey revokes the key if it is not already revoked.
+func (r *APIKeyRepository) RevokeKey(ctx context.Context, id string) error {
+	return inAllTenants(ctx, r.db, func(q querier) error {
+		result, err := q.ExecContext(ctx,
+			`UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`,
+			id,
+		)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err == nil && n == 0 {
+			return ErrNotFound
+		}
+		return err
+	})
+}
+
+// TouchKey records that the key was just used.
+func (r *APIKeyRepository) TouchKey(ctx context.Context, id string) error {
+	return inAllTenants(ctx, r.db, func(q querier) error {
+		_, err := q.ExecContext(ctx, `UPDATE api_keys SET last_used_at = NOW() WHERE id = $1`, id)
+		return err
+	})
+}
+
+func scanAPIKey(row rowScanner) (*model.APIKey, error) {
+	key := &model.APIKey{}
+	var scopes pq.StringArray
+
+	err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.TenantID,
+		&key.KeyPrefix,
+		&key.KeyHash,
+		&scopes,
+		&key.RateLimit,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedBy,
+		&key.CreatedAt,
+		&key.UpdatedAt,
+	)
+	key.Scopes = []string(scopes)
+
+	return key, err
+}
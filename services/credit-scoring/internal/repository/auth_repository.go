@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"credit-scoring/internal/model"
+)
+
+// ErrTokenUsed is returned when a refresh token has already been used or
+// revoked.
+var ErrTokenUsed = errors.New("refresh token already used")
+
+// AuthRepository stores users, API clients and refresh tokens. They are
+// looked up before the caller's tenant is known, so queries aren't scoped to
+// one; each user and client records the tenant its tokens act for.
+type AuthRepository struct {
+	db *sql.DB
+}
+
+func NewAuthRepository(db *sql.DB) *AuthRepository {
+	return &AuthRepository{db: db}
+}
+
+func (r *AuthRepository) CreateUser(ctx context.Context, user *model.User) error {
+	query := `
+		INSERT INTO users (id, tenant_id, email, password_hash, role, active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		user.ID,
+		user.TenantID,
+		user.Email,
+		user.PasswordHash,
+		user.Role,
+		user.Active,
+	).Scan(&user.CreatedAt, &user.UpdatedAt)
+}
+
+const userColumns = `id, tenant_id, email, password_hash, role, active, created_at, updated_at`
+
+// GetUserByEmail matches the email case-insensitively.
+func (r *AuthRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE LOWER(email) = LOWER($1)`
+	return r.getUser(ctx, query, email)
+}
+
+func (r *AuthRepository) GetUser(ctx context.Context, id string) (*model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	return r.getUser(ctx, query, id)
+}
+
+func (r *AuthRepository) getUser(ctx context.Context, query string, arg string) (*model.User, error) {
+	user := &model.User{}
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(
+		&user.ID,
+		&user.TenantID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.Role,
+		&user.Active,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return user, err
+}
+
+func (r *AuthRepository) CreateClient(ctx context.Context, client *model.APIClient) error {
+	query := `
+		INSERT INTO api_clients (id, tenant_id, name, secret_hash, role, active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		client.ID,
+		client.TenantID,
+		client.Name,
+		client.SecretHash,
+		client.Role,
+		client.Active,
+	).Scan(&client.CreatedAt, &client.UpdatedAt)
+}
+
+func (r *AuthRepository) GetClient(ctx context.Context, id string) (*model.APIClient, error) {
+	query := `SELECT id, tenant_id, name, secret_hash, role, active, created_at, updated_at FROM api_clients WHERE id = $1`
+
+	client := &model.APIClient{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&client.ID,
+		&client.TenantID,
+		&client.Name,
+		&client.SecretHash,
+		&client.Role,
+		&client.Active,
+		&client.CreatedAt,
+		&client.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return client, err
+}
+
+func (r *AuthRepository) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	return createRefreshToken(ctx, r.db, token)
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func createRefreshToken(ctx context.Context, db queryRower, token *model.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, session_id, user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`
+	return db.QueryRowContext(ctx, query,
+		token.ID,
+		token.SessionID,
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt,
+	).Scan(&token.CreatedAt)
+}
+
+func (r *AuthRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	query := `
+		SELECT id, session_id, user_id, token_hash, expires_at, used_at, revoked_at, created_at
+		FROM refresh_tokens WHERE token_hash = $1
+	`
+
+	token := &model.RefreshToken{}
+	err := r.db.QueryRowContext(ctx, query, hash).Scan(
+		&token.ID,
+		&token.SessionID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return token, err
+}
+
+// RotateRefreshToken marks usedID used and stores next in one transaction.
+// It returns ErrTokenUsed if usedID was used or revoked in the meantime, so
+// a token raced by two clients is only exchanged once.
+func (r *AuthRepository) RotateRefreshToken(ctx context.Context, usedID string, next *model.RefreshToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL`,
+		usedID,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrTokenUsed
+	}
+
+	if err := createRefreshToken(ctx, tx, next); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RevokeSession revokes every refresh token in the session.
+func (r *AuthRepository) RevokeSession(ctx context.Context, sessionID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = NOW() WHERE session_id = $1 AND revoked_at IS NULL`,
+		sessionID,
+	)
+	return err
+}
@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
@@ -10,6 +13,9 @@ import (
 	"credit-scoring/internal/model"
 )
 
+// CreditRepository stores credit scores and scoring inputs. Every query is
+// scoped to the tenant in the context, except ClaimExpired, which serves the
+// expiry job for all tenants.
 type CreditRepository struct {
 	db *sql.DB
 }
@@ -18,68 +24,235 @@ func NewCreditRepository(db *sql.DB) *CreditRepository {
 	return &CreditRepository{db: db}
 }
 
+// Create stores score for the context's tenant and sets its TenantID.
 func (r *CreditRepository) Create(ctx context.Context, score *model.CreditScore) error {
 	query := `
-		INSERT INTO credit_scores (id, user_id, score, grade, factors, recommendation, calculated_at, expires_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO credit_scores (id, tenant_id, user_id, score, grade, factors, recommendation, model_version, sub_scores, reason_codes, calculated_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
-	_, err := r.db.ExecContext(ctx, query,
-		score.ID,
-		score.UserID,
-		score.Score,
-		score.Grade,
-		pq.Array(score.Factors),
-		score.Recommendation,
-		score.CalculatedAt,
-		score.ExpiresAt,
-	)
-	return err
+	subScores, err := json.Marshal(score.SubScores)
+	if err != nil {
+		return err
+	}
+	if score.SubScores == nil {
+		subScores = []byte("[]")
+	}
+
+	return inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		score.TenantID = tenantID
+		_, err := q.ExecContext(ctx, query,
+			score.ID,
+			score.TenantID,
+			score.UserID,
+			score.Score,
+			score.Grade,
+			pq.Array(score.Factors),
+			score.Recommendation,
+			score.ModelVersion,
+			subScores,
+			pq.Array(score.ReasonCodes),
+			score.CalculatedAt,
+			score.ExpiresAt,
+		)
+		return err
+	})
 }
 
 func (r *CreditRepository) GetLatestByUserID(ctx context.Context, userID string) (*model.CreditScore, error) {
 	query := `
-		SELECT id, user_id, score, grade, factors, recommendation, calculated_at, expires_at, created_at, updated_at
+		SELECT ` + creditScoreColumns + `
 		FROM credit_scores
-		WHERE user_id = $1
+		WHERE tenant_id = $1 AND user_id = $2
 		ORDER BY calculated_at DESC
 		LIMIT 1
 	`
-	
-	score := &model.CreditScore{}
-	var factors pq.StringArray
-	
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
-		&score.ID,
-		&score.UserID,
-		&score.Score,
-		&score.Grade,
-		&factors,
-		&score.Recommendation,
-		&score.CalculatedAt,
-		&score.ExpiresAt,
-		&score.CreatedAt,
-		&score.UpdatedAt,
-	)
-	
-	score.Factors = []string(factors)
-	
+
+	var score *model.CreditScore
+	err := inTenant(ctx, r.db, func(q querier, tenantID string) (err error) {
+		score, err = scanCreditScore(q.QueryRowContext(ctx, query, tenantID, userID))
+		return err
+	})
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
 	}
-	
+
 	return score, err
 }
 
-func (r *CreditRepository) GetHistoryByUserID(ctx context.Context, userID string, limit int) ([]*model.CreditScore, error) {
+// HistoryFilter selects a page of a user's score history, newest first.
+// Zero values leave the corresponding bound open.
+type HistoryFilter struct {
+	UserID string
+	From   time.Time
+	To     time.Time
+	Grade  string
+	Limit  int
+
+	// Keyset cursor: only scores strictly older than (AfterCalculatedAt, AfterID)
+	AfterCalculatedAt time.Time
+	AfterID           string
+}
+
+func (r *CreditRepository) GetHistory(ctx context.Context, filter HistoryFilter) ([]*model.CreditScore, error) {
+	var scores []*model.CreditScore
+	err := inTenant(ctx, r.db, func(q querier, tenantID string) (err error) {
+		query := `
+			SELECT ` + creditScoreColumns + `
+			FROM credit_scores
+			WHERE tenant_id = $1 AND user_id = $2`
+		args := []interface{}{tenantID, filter.UserID}
+
+		if !filter.From.IsZero() {
+			args = append(args, filter.From)
+			query += fmt.Sprintf(" AND calculated_at >= $%d", len(args))
+		}
+		if !filter.To.IsZero() {
+			args = append(args, filter.To)
+			query += fmt.Sprintf(" AND calculated_at < $%d", len(args))
+		}
+		if filter.Grade != "" {
+			args = append(args, filter.Grade)
+			query += fmt.Sprintf(" AND grade = $%d", len(args))
+		}
+		if filter.AfterID != "" {
+			// Row comparison matches idx_credit_scores_tenant_user_calculated_id
+			args = append(args, filter.AfterCalculatedAt, filter.AfterID)
+			query += fmt.Sprintf(" AND (calculated_at, id) < ($%d, $%d)", len(args)-1, len(args))
+		}
+
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" ORDER BY calculated_at DESC, id DESC LIMIT $%d", len(args))
+
+		scores, err = queryCreditScores(ctx, q, query, args...)
+		return err
+	})
+
+	return scores, err
+}
+
+// GetRange returns up to limit scores calculated in [from, to), oldest first.
+func (r *CreditRepository) GetRange(ctx context.Context, userID string, from, to time.Time, limit int) ([]*model.CreditScore, error) {
 	query := `
-		SELECT id, user_id, score, grade, factors, recommendation, calculated_at, expires_at, created_at, updated_at
+		SELECT ` + creditScoreColumns + `
 		FROM credit_scores
-		WHERE user_id = $1
-		ORDER BY calculated_at DESC
-		LIMIT $2
+		WHERE tenant_id = $1 AND user_id = $2 AND calculated_at >= $3 AND calculated_at < $4
+		ORDER BY calculated_at ASC, id ASC
+		LIMIT $5
 	`
-	
-	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+
+	var scores []*model.CreditScore
+	err := inTenant(ctx, r.db, func(q querier, tenantID string) (err error) {
+		scores, err = queryCreditScores(ctx, q, query, tenantID, userID, from, to, limit)
+		return err
+	})
+
+	return scores, err
+}
+
+func (r *CreditRepository) UpsertInputs(ctx context.Context, inputs *model.CreditScoreInputs) error {
+	query := `
+		INSERT INTO credit_score_inputs (tenant_id, user_id, income_amount, employment_status, account_age, transaction_data, loan_history)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+			income_amount = EXCLUDED.income_amount,
+			employment_status = EXCLUDED.employment_status,
+			account_age = EXCLUDED.account_age,
+			transaction_data = EXCLUDED.transaction_data,
+			loan_history = EXCLUDED.loan_history
+	`
+
+	return inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		_, err := q.ExecContext(ctx, query,
+			tenantID,
+			inputs.UserID,
+			inputs.IncomeAmount,
+			inputs.EmploymentStatus,
+			inputs.AccountAge,
+			[]byte(inputs.TransactionData),
+			[]byte(inputs.LoanHistory),
+		)
+		return err
+	})
+}
+
+func (r *CreditRepository) GetInputsByUserID(ctx context.Context, userID string) (*model.CreditScoreInputs, error) {
+	query := `
+		SELECT user_id, income_amount, employment_status, account_age, transaction_data, loan_history, created_at, updated_at
+		FROM credit_score_inputs
+		WHERE tenant_id = $1 AND user_id = $2
+	`
+
+	inputs := &model.CreditScoreInputs{}
+	var transactionData, loanHistory []byte
+
+	err := inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		return q.QueryRowContext(ctx, query, tenantID, userID).Scan(
+			&inputs.UserID,
+			&inputs.IncomeAmount,
+			&inputs.EmploymentStatus,
+			&inputs.AccountAge,
+			&transactionData,
+			&loanHistory,
+			&inputs.CreatedAt,
+			&inputs.UpdatedAt,
+		)
+	})
+
+	inputs.TransactionData = transactionData
+	inputs.LoanHistory = loanHistory
+
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+
+	return inputs, err
+}
+
+// ClaimExpired marks up to limit expired scores of any tenant as notified and
+// returns them. Only a user's latest score counts; older scores were
+// superseded, not expired.
+func (r *CreditRepository) ClaimExpired(ctx context.Context, limit int) ([]*model.CreditScore, error) {
+	query := `
+		UPDATE credit_scores cs
+		SET expiry_notified_at = NOW()
+		FROM (
+			SELECT id FROM credit_scores c
+			WHERE c.expires_at <= NOW() AND c.expiry_notified_at IS NULL
+				AND NOT EXISTS (
+					SELECT 1 FROM credit_scores newer
+					WHERE newer.tenant_id = c.tenant_id AND newer.user_id = c.user_id
+						AND newer.calculated_at > c.calculated_at
+				)
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		) expired
+		WHERE cs.id = expired.id
+		RETURNING ` + creditScoreColumnsAs("cs") + `
+	`
+
+	var scores []*model.CreditScore
+	err := inAllTenants(ctx, r.db, func(q querier) (err error) {
+		scores, err = queryCreditScores(ctx, q, query, limit)
+		return err
+	})
+
+	return scores, err
+}
+
+var ErrNotFound = sql.ErrNoRows
+
+// creditScoreColumns are the credit_scores columns read by scanCreditScore
+const creditScoreColumns = "id, tenant_id, user_id, score, grade, factors, recommendation, model_version, sub_scores, reason_codes, calculated_at, expires_at, created_at, updated_at"
+
+// creditScoreColumnsAs qualifies creditScoreColumns with a table alias
+func creditScoreColumnsAs(alias string) string {
+	return alias + "." + strings.ReplaceAll(creditScoreColumns, ", ", ", "+alias+".")
+}
+
+// queryCreditScores runs a query returning creditScoreColumns and scans every
+// row.
+func queryCreditScores(ctx context.Context, q querier, query string, args ...interface{}) ([]*model.CreditScore, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -87,29 +260,45 @@ func (r *CreditRepository) GetHistoryByUserID(ctx context.Context, userID string
 
 	var scores []*model.CreditScore
 	for rows.Next() {
-		score := &model.CreditScore{}
-		var factors pq.StringArray
-		
-		if err := rows.Scan(
-			&score.ID,
-			&score.UserID,
-			&score.Score,
-			&score.Grade,
-			&factors,
-			&score.Recommendation,
-			&score.CalculatedAt,
-			&score.ExpiresAt,
-			&score.CreatedAt,
-			&score.UpdatedAt,
-		); err != nil {
+		score, err := scanCreditScore(rows)
+		if err != nil {
 			return nil, err
 		}
-		
-		score.Factors = []string(factors)
 		scores = append(scores, score)
 	}
 
 	return scores, rows.Err()
 }
 
-var ErrNotFound = sql.ErrNoRows
+func scanCreditScore(row rowScanner) (*model.CreditScore, error) {
+	score := &model.CreditScore{}
+	var factors, reasonCodes pq.StringArray
+	var subScores []byte
+
+	if err := row.Scan(
+		&score.ID,
+		&score.TenantID,
+		&score.UserID,
+		&score.Score,
+		&score.Grade,
+		&factors,
+		&score.Recommendation,
+		&score.ModelVersion,
+		&subScores,
+		&reasonCodes,
+		&score.CalculatedAt,
+		&score.ExpiresAt,
+		&score.CreatedAt,
+		&score.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	score.Factors = []string(factors)
+	score.ReasonCodes = []string(reasonCodes)
+	if err := json.Unmarshal(subScores, &score.SubScores); err != nil {
+		return nil, fmt.Errorf("failed to decode sub-scores: %w", err)
+	}
+
+	return score, nil
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"credit-scoring/internal/model"
+)
+
+// ExportRepository reads credit_scores for warehouse exports, which cover
+// every tenant; each row carries its tenant ID.
+type ExportRepository struct {
+	db *sql.DB
+}
+
+func NewExportRepository(db *sql.DB) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+// ExportFilter selects credit_scores rows in (created_at, id) order. Zero
+// values leave the corresponding bound open.
+type ExportFilter struct {
+	From time.Time
+	To   time.Time
+
+	// Watermark: only rows strictly after (AfterCreatedAt, AfterID)
+	AfterCreatedAt time.Time
+	AfterID        string
+}
+
+// StreamScores calls fn for every matching row without loading them all into
+// memory. Iteration stops at the first error fn returns.
+func (r *ExportRepository) StreamScores(ctx context.Context, filter ExportFilter, fn func(*model.CreditScore) error) error {
+	query := `
+		SELECT id, tenant_id, user_id, score, grade, factors, recommendation, calculated_at, expires_at, created_at, updated_at
+		FROM credit_scores
+		WHERE TRUE`
+	var args []interface{}
+
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		query += fmt.Sprintf(" AND created_at >= $%d", len(args))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		query += fmt.Sprintf(" AND created_at < $%d", len(args))
+	}
+	if filter.AfterID != "" {
+		// Row comparison matches idx_credit_scores_created_id
+		args = append(args, filter.AfterCreatedAt, filter.AfterID)
+		query += fmt.Sprintf(" AND (created_at, id) > ($%d, $%d)", len(args)-1, len(args))
+	}
+	query += " ORDER BY created_at, id"
+
+	return inAllTenants(ctx, r.db, func(q querier) error {
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			score := &model.CreditScore{}
+			var factors pq.StringArray
+
+			if err := rows.Scan(
+				&score.ID,
+				&score.TenantID,
+				&score.UserID,
+				&score.Score,
+				&score.Grade,
+				&factors,
+				&score.Recommendation,
+				&score.CalculatedAt,
+				&score.ExpiresAt,
+				&score.CreatedAt,
+				&score.UpdatedAt,
+			); err != nil {
+				return err
+			}
+
+			score.Factors = []string(factors)
+			if err := fn(score); err != nil {
+				return err
+			}
+		}
+
+		return rows.Err()
+	})
+}
+
+// GetWatermark returns the job's watermark, or ErrNotFound before its first
+// non-empty run.
+func (r *ExportRepository) GetWatermark(ctx context.Context, job string) (*model.ExportWatermark, error) {
+	query := `
+		SELECT job, last_created_at, last_id, last_location, last_row_count, created_at, updated_at
+		FROM export_watermarks
+		WHERE job = $1
+	`
+
+	wm := &model.ExportWatermark{}
+	err := r.db.QueryRowContext(ctx, query, job).Scan(
+		&wm.Job,
+		&wm.LastCreatedAt,
+		&wm.LastID,
+		&wm.LastLocation,
+		&wm.LastRowCount,
+		&wm.CreatedAt,
+		&wm.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return wm, nil
+}
+
+func (r *ExportRepository) SaveWatermark(ctx context.Context, wm *model.ExportWatermark) error {
+	query := `
+		INSERT INTO export_watermarks (job, last_created_at, last_id, last_location, last_row_count)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (job) DO UPDATE SET
+			last_created_at = EXCLUDED.last_created_at,
+			last_id = EXCLUDED.last_id,
+			last_location = EXCLUDED.last_location,
+			last_row_count = EXCLUDED.last_row_count
+	`
+	_, err := r.db.ExecContext(ctx, query, wm.Job, wm.LastCreatedAt, wm.LastID, wm.LastLocation, wm.LastRowCount)
+	return err
+}
+
+// TryLock takes a session advisory lock on job so concurrent runs can't export
+// the same rows twice. ok is false if another run holds it. The lock is tied
+// to a dedicated connection and released by unlock.
+func (r *ExportRepository) TryLock(ctx context.Context, job string) (unlock func(), ok bool, err error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext('export:' || $1))`, job).Scan(&ok); err != nil || !ok {
+		conn.Close()
+		return nil, false, err
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext('export:' || $1))`, job)
+		conn.Close()
+	}, true, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"credit-scoring/internal/model"
+)
+
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+func (r *IdempotencyRepository) Get(ctx context.Context, principal, key string) (*model.IdempotencyKey, error) {
+	query := `
+		SELECT idempotency_key, principal, request_hash, status_code, response_body, content_type, expires_at, created_at
+		FROM idempotency_keys
+		WHERE tenant_id = $1 AND idempotency_key = $2 AND principal = $3 AND expires_at > NOW()
+	`
+
+	record := &model.IdempotencyKey{}
+	err := inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		return q.QueryRowContext(ctx, query, tenantID, key, principal).Scan(
+			&record.Key,
+			&record.Principal,
+			&record.RequestHash,
+			&record.StatusCode,
+			&record.ResponseBody,
+			&record.ContentType,
+			&record.ExpiresAt,
+			&record.CreatedAt,
+		)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+
+	return record, err
+}
+
+// Save stores a response, replacing an expired record for the same key.
+func (r *IdempotencyRepository) Save(ctx context.Context, record *model.IdempotencyKey) error {
+	query := `
+		INSERT INTO idempotency_keys (tenant_id, idempotency_key, principal, request_hash, status_code, response_body, content_type, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (tenant_id, idempotency_key, principal) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status_code = EXCLUDED.status_code,
+			response_body = EXCLUDED.response_body,
+			content_type = EXCLUDED.content_type,
+			expires_at = EXCLUDED.expires_at,
+			created_at = CURRENT_TIMESTAMP
+		WHERE idempotency_keys.expires_at <= NOW()
+	`
+
+	return inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		_, err := q.ExecContext(ctx, query,
+			tenantID,
+			record.Key,
+			record.Principal,
+			record.RequestHash,
+			record.StatusCode,
+			record.ResponseBody,
+			record.ContentType,
+			record.ExpiresAt,
+		)
+		return err
+	})
+}
+
+// DeleteExpired removes expired keys of every tenant.
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	var deleted int64
+	err := inAllTenants(ctx, r.db, func(q querier) error {
+		result, err := q.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`)
+		if err != nil {
+			return err
+		}
+		deleted, err = result.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
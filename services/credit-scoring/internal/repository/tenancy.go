@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"credit-scoring/internal/tenant"
+	"credit-scoring/pkg/database"
+	"credit-scoring/pkg/errors"
+)
+
+// Tenant-scoped tables have row-level security policies (migrations 016, 017
+// and 020) that only admit rows of the tenant named by the app.tenant_id
+// setting, or of any tenant when app.all_tenants is on. Queries on them run
+// through inTenant or inAllTenants, which set these for a single transaction,
+// so a connection returned to the pool carries neither. A query that forgets
+// its tenant_id filter still can't see or change another tenant's rows.
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// inTenant runs fn in a transaction confined to the context's tenant, which
+// it is also passed for use in filters and inserts.
+func inTenant(ctx context.Context, db *sql.DB, fn func(q querier, tenantID string) error) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	return inTx(ctx, db, "app.tenant_id", tenantID, func(q querier) error {
+		return fn(q, tenantID)
+	})
+}
+
+// inAllTenants runs fn in a transaction that may see every tenant's rows, for
+// background jobs and exports that work across tenants.
+func inAllTenants(ctx context.Context, db *sql.DB, fn func(q querier) error) error {
+	return inTx(ctx, db, "app.all_tenants", "on", fn)
+}
+
+func inTx(ctx context.Context, db *sql.DB, setting, value string, fn func(q querier) error) (err error) {
+	// Callers can ask the client to retry when the database is down
+	defer func() {
+		if database.IsUnavailable(err) {
+			err = errors.Unavailable("The database is unavailable", err)
+		}
+	}()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// is_local = true: the setting ends with the transaction
+	if _, err := tx.ExecContext(ctx, `SELECT set_config($1, $2, true)`, setting, value); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"credit-scoring/internal/model"
+)
+
+type TenantRepository struct {
+	db *sql.DB
+}
+
+func NewTenantRepository(db *sql.DB) *TenantRepository {
+	return &TenantRepository{db: db}
+}
+
+func (r *TenantRepository) CreateTenant(ctx context.Context, t *model.Tenant) error {
+	scoringModel, err := marshalScoringModel(t.ScoringModel)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO tenants (id, name, scoring_model)
+		VALUES ($1, $2, $3)
+		RETURNING created_at, updated_at
+	`
+	return r.db.QueryRowContext(ctx, query, t.ID, t.Name, scoringModel).Scan(&t.CreatedAt, &t.UpdatedAt)
+}
+
+func (r *TenantRepository) GetTenant(ctx context.Context, id string) (*model.Tenant, error) {
+	query := `SELECT id, name, scoring_model, monthly_score_quota, created_at, updated_at FROM tenants WHERE id = $1`
+
+	t := &model.Tenant{}
+	var scoringModel []byte
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&t.ID,
+		&t.Name,
+		&scoringModel,
+		&t.MonthlyScoreQuota,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if scoringModel != nil {
+		t.ScoringModel = &model.ScoringModel{}
+		if err := json.Unmarshal(scoringModel, t.ScoringModel); err != nil {
+			return nil, fmt.Errorf("failed to decode scoring model: %w", err)
+		}
+	}
+
+	return t, nil
+}
+
+// SetScoringModel replaces the tenant's scoring model; nil restores the
+// built-in model.
+func (r *TenantRepository) SetScoringModel(ctx context.Context, id string, m *model.ScoringModel) error {
+	scoringModel, err := marshalScoringModel(m)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, `UPDATE tenants SET scoring_model = $2 WHERE id = $1`, id, scoringModel)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// SetMonthlyScoreQuota sets how many billable scoring requests the tenant may
+// make per month; zero removes the limit.
+func (r *TenantRepository) SetMonthlyScoreQuota(ctx context.Context, id string, quota int) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE tenants SET monthly_score_quota = $2 WHERE id = $1`, id, quota)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// marshalScoringModel encodes m for the scoring_model column, which is NULL
+// for the built-in model.
+func marshalScoringModel(m *model.ScoringModel) (interface{}, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
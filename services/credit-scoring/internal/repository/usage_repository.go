@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"credit-scoring/internal/model"
+)
+
+// UsageGranularity selects the daily or the monthly usage table.
+type UsageGranularity string
+
+const (
+	UsageDaily   UsageGranularity = "day"
+	UsageMonthly UsageGranularity = "month"
+)
+
+// usageTable is a usage table and the column holding its period
+type usageTable struct {
+	name   string
+	period string
+}
+
+var usageTables = map[UsageGranularity]usageTable{
+	UsageDaily:   {"usage_daily", "day"},
+	UsageMonthly: {"usage_monthly", "month"},
+}
+
+// UsageRepository stores the request counts rolled up from Redis. Every query
+// is scoped to the tenant in the context.
+type UsageRepository struct {
+	db *sql.DB
+}
+
+func NewUsageRepository(db *sql.DB) *UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+// Upsert stores the context tenant's counts for period. Counts only grow
+// within a period, so a stored count is never lowered: a rollup from a Redis
+// that lost its counters can't erase usage already recorded.
+func (r *UsageRepository) Upsert(ctx context.Context, granularity UsageGranularity, period time.Time, records []model.UsageRecord) error {
+	t, err := usageTableOf(granularity)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (tenant_id, %[2]s, caller_id, endpoint, billable, request_count)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (tenant_id, %[2]s, caller_id, endpoint) DO UPDATE SET
+			billable = EXCLUDED.billable,
+			request_count = GREATEST(%[1]s.request_count, EXCLUDED.request_count)
+	`, t.name, t.period)
+
+	return inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		for _, rec := range records {
+			if _, err := q.ExecContext(ctx, query,
+				tenantID,
+				period,
+				rec.CallerID,
+				rec.Endpoint,
+				rec.Billable,
+				rec.RequestCount,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// List returns the context tenant's counts for the periods starting in
+// [from, to], oldest first.
+func (r *UsageRepository) List(ctx context.Context, granularity UsageGranularity, from, to time.Time) ([]model.UsageRecord, error) {
+	t, err := usageTableOf(granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT tenant_id, %[2]s, caller_id, endpoint, billable, request_count
+		FROM %[1]s
+		WHERE tenant_id = $1 AND %[2]s >= $2 AND %[2]s <= $3
+		ORDER BY %[2]s ASC, caller_id ASC, endpoint ASC
+	`, t.name, t.period)
+
+	var records []model.UsageRecord
+	err = inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		rows, err := q.QueryContext(ctx, query, tenantID, from, to)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var rec model.UsageRecord
+			if err := rows.Scan(
+				&rec.TenantID,
+				&rec.Period,
+				&rec.CallerID,
+				&rec.Endpoint,
+				&rec.Billable,
+				&rec.RequestCount,
+			); err != nil {
+				return err
+			}
+			records = append(records, rec)
+		}
+		return rows.Err()
+	})
+
+	return records, err
+}
+
+// MonthlyBillable returns how many billable requests the context tenant made
+// in the month starting at month, as of the last rollup.
+func (r *UsageRepository) MonthlyBillable(ctx context.Context, month time.Time) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(request_count), 0)
+		FROM usage_monthly
+		WHERE tenant_id = $1 AND month = $2 AND billable
+	`
+
+	var total int64
+	err := inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		return q.QueryRowContext(ctx, query, tenantID, month).Scan(&total)
+	})
+
+	return total, err
+}
+
+func usageTableOf(granularity UsageGranularity) (usageTable, error) {
+	t, ok := usageTables[granularity]
+	if !ok {
+		return t, fmt.Errorf("unknown usage granularity %q", granularity)
+	}
+	return t, nil
+}
@@ -0,0 +1,391 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	"credit-scoring/internal/model"
+)
+
+// WebhookRepository stores subscriptions and their deliveries. Queries are
+// scoped to the tenant in the context, except ClaimDueDeliveries, which the
+// delivery worker runs for all tenants. Deliveries and their attempts carry
+// their subscription's tenant.
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (id, tenant_id, owner_id, url, event_types, secret, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at
+	`
+	return inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		return q.QueryRowContext(ctx, query,
+			sub.ID,
+			tenantID,
+			sub.OwnerID,
+			sub.URL,
+			pq.Array(sub.EventTypes),
+			sub.Secret,
+			sub.Active,
+		).Scan(&sub.CreatedAt, &sub.UpdatedAt)
+	})
+}
+
+const subscriptionColumns = `id, owner_id, url, event_types, secret, active, created_at, updated_at`
+
+// GetSubscription returns the subscription only if ownerID owns it.
+func (r *WebhookRepository) GetSubscription(ctx context.Context, ownerID, id string) (*model.WebhookSubscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2 AND owner_id = $3`
+
+	var sub *model.WebhookSubscription
+	err := inTenant(ctx, r.db, func(q querier, tenantID string) (err error) {
+		sub, err = scanSubscription(q.QueryRowContext(ctx, query, id, tenantID, ownerID))
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return sub, err
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context, ownerID string) ([]*model.WebhookSubscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE tenant_id = $1 AND owner_id = $2 ORDER BY created_at DESC`
+	return r.querySubscriptions(ctx, query, ownerID)
+}
+
+// ListSubscriptionsForEvent returns the tenant's active subscriptions to
+// eventType.
+func (r *WebhookRepository) ListSubscriptionsForEvent(ctx context.Context, eventType string) ([]*model.WebhookSubscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE tenant_id = $1 AND active AND $2 = ANY(event_types)`
+	return r.querySubscriptions(ctx, query, eventType)
+}
+
+// DeleteSubscription removes the subscription and its delivery history.
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, ownerID, id string) error {
+	return inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		result, err := q.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2 AND owner_id = $3`, id, tenantID, ownerID)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err == nil && n == 0 {
+			return ErrNotFound
+		}
+		return err
+	})
+}
+
+// querySubscriptions runs a query whose first parameter is the tenant ID.
+func (r *WebhookRepository) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]*model.WebhookSubscription, error) {
+	var subs []*model.WebhookSubscription
+	err := inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		rows, err := q.QueryContext(ctx, query, append([]interface{}{tenantID}, args...)...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			sub, err := scanSubscription(rows)
+			if err != nil {
+				return err
+			}
+			subs = append(subs, sub)
+		}
+		return rows.Err()
+	})
+
+	return subs, err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSubscription(row rowScanner) (*model.WebhookSubscription, error) {
+	sub := &model.WebhookSubscription{}
+	var eventTypes pq.StringArray
+
+	err := row.Scan(
+		&sub.ID,
+		&sub.OwnerID,
+		&sub.URL,
+		&eventTypes,
+		&sub.Secret,
+		&sub.Active,
+		&sub.CreatedAt,
+		&sub.UpdatedAt,
+	)
+	sub.EventTypes = []string(eventTypes)
+
+	return sub, err
+}
+
+// CreateDelivery queues a delivery as a pending WEBHOOK notification.
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, d *model.WebhookDelivery) error {
+	query := `
+		INSERT INTO notifications (
+			id, tenant_id, user_id, notification_type, channel, recipient, content, status, provider,
+			webhook_subscription_id, next_attempt_at
+		)
+		VALUES ($1, $2, $3, 'WEBHOOK', $4, $5, $6, 'PENDING', 'webhook', $7, NOW())
+		RETURNING created_at
+	`
+	return inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		return q.QueryRowContext(ctx, query,
+			d.ID,
+			tenantID,
+			d.UserID,
+			d.EventType,
+			d.URL,
+			d.Payload,
+			d.SubscriptionID,
+		).Scan(&d.CreatedAt)
+	})
+}
+
+// DueWebhookDelivery is a pending delivery along with its tenant and signing
+// secret.
+type DueWebhookDelivery struct {
+	model.WebhookDelivery
+	TenantID string
+	Secret   string
+}
+
+// ClaimDueDeliveries returns up to limit deliveries that are due and pushes
+// their next attempt back by lease, so other replicas skip them while this
+// one is sending.
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*DueWebhookDelivery, error) {
+	query := `
+		UPDATE notifications n
+		SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+		FROM (
+			SELECT id FROM notifications
+			WHERE notification_type = 'WEBHOOK' AND status = 'PENDING' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		) due, webhook_subscriptions s
+		WHERE n.id = due.id AND s.id = n.webhook_subscription_id AND s.tenant_id = n.tenant_id
+		RETURNING n.id, n.tenant_id, n.webhook_subscription_id, n.user_id, n.channel, n.recipient, n.content, n.retry_count, s.secret
+	`
+
+	var due []*DueWebhookDelivery
+	err := inAllTenants(ctx, r.db, func(q querier) error {
+		rows, err := q.QueryContext(ctx, query, limit, lease.Milliseconds())
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			d := &DueWebhookDelivery{}
+			if err := rows.Scan(
+				&d.ID,
+				&d.TenantID,
+				&d.SubscriptionID,
+				&d.UserID,
+				&d.EventType,
+				&d.URL,
+				&d.Payload,
+				&d.RetryCount,
+				&d.Secret,
+			); err != nil {
+				return err
+			}
+			due = append(due, d)
+		}
+		return rows.Err()
+	})
+
+	return due, err
+}
+
+// RecordAttempt logs an attempt, numbering it after the delivery's previous
+// attempts.
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, a *model.WebhookDeliveryAttempt) error {
+	query := `
+		INSERT INTO webhook_delivery_attempts (tenant_id, notification_id, attempt, status_code, error, duration_ms)
+		SELECT $1::VARCHAR, $2::VARCHAR, COUNT(*) + 1, $3::INTEGER, $4::TEXT, $5::INTEGER
+		FROM webhook_delivery_attempts WHERE tenant_id = $1 AND notification_id = $2
+		RETURNING id, attempt, attempted_at
+	`
+	return inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		return q.QueryRowContext(ctx, query,
+			tenantID,
+			a.NotificationID,
+			a.StatusCode,
+			a.Error,
+			a.DurationMs,
+		).Scan(&a.ID, &a.Attempt, &a.AttemptedAt)
+	})
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id string) error {
+	query := `
+		UPDATE notifications
+		SET status = 'DELIVERED', sent_at = NOW(), delivered_at = NOW(), next_attempt_at = NULL, failure_reason = NULL
+		WHERE id = $1 AND tenant_id = $2
+	`
+	return r.updateDelivery(ctx, query, id)
+}
+
+// MarkRetry records a failed attempt and schedules the next one.
+func (r *WebhookRepository) MarkRetry(ctx context.Context, id, reason string, next time.Time) error {
+	query := `
+		UPDATE notifications
+		SET retry_count = retry_count + 1, sent_at = NOW(), failure_reason = $3, next_attempt_at = $4
+		WHERE id = $1 AND tenant_id = $2
+	`
+	return r.updateDelivery(ctx, query, id, reason, next)
+}
+
+// MarkFailed records a failed attempt after which no retry is scheduled.
+func (r *WebhookRepository) MarkFailed(ctx context.Context, id, reason string) error {
+	query := `
+		UPDATE notifications
+		SET status = 'FAILED', retry_count = retry_count + 1, sent_at = NOW(), failure_reason = $3, next_attempt_at = NULL
+		WHERE id = $1 AND tenant_id = $2
+	`
+	return r.updateDelivery(ctx, query, id, reason)
+}
+
+// updateDelivery runs an update of the delivery in parameter $1, whose
+// second parameter is the tenant ID.
+func (r *WebhookRepository) updateDelivery(ctx context.Context, query, id string, args ...interface{}) error {
+	return inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		_, err := q.ExecContext(ctx, query, append([]interface{}{id, tenantID}, args...)...)
+		return err
+	})
+}
+
+// Redeliver queues a delivery to be sent again now with a fresh retry budget.
+func (r *WebhookRepository) Redeliver(ctx context.Context, subscriptionID, id string) error {
+	query := `
+		UPDATE notifications
+		SET status = 'PENDING', retry_count = 0, failure_reason = NULL, next_attempt_at = NOW()
+		WHERE id = $1 AND webhook_subscription_id = $2 AND tenant_id = $3
+	`
+	return inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		result, err := q.ExecContext(ctx, query, id, subscriptionID, tenantID)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err == nil && n == 0 {
+			return ErrNotFound
+		}
+		return err
+	})
+}
+
+const deliveryColumns = `
+	id, webhook_subscription_id, user_id, channel, recipient, content, status, retry_count,
+	next_attempt_at, sent_at, delivered_at, failure_reason, created_at
+`
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*model.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM notifications
+		WHERE webhook_subscription_id = $1 AND tenant_id = $3
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	var deliveries []*model.WebhookDelivery
+	err := inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		rows, err := q.QueryContext(ctx, query, subscriptionID, limit, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			d, err := scanDelivery(rows)
+			if err != nil {
+				return err
+			}
+			deliveries = append(deliveries, d)
+		}
+		return rows.Err()
+	})
+
+	return deliveries, err
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, subscriptionID, id string) (*model.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM notifications WHERE id = $1 AND webhook_subscription_id = $2 AND tenant_id = $3`
+
+	var d *model.WebhookDelivery
+	err := inTenant(ctx, r.db, func(q querier, tenantID string) (err error) {
+		d, err = scanDelivery(q.QueryRowContext(ctx, query, id, subscriptionID, tenantID))
+		return err
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return d, err
+}
+
+func scanDelivery(row rowScanner) (*model.WebhookDelivery, error) {
+	d := &model.WebhookDelivery{}
+	err := row.Scan(
+		&d.ID,
+		&d.SubscriptionID,
+		&d.UserID,
+		&d.EventType,
+		&d.URL,
+		&d.Payload,
+		&d.Status,
+		&d.RetryCount,
+		&d.NextAttemptAt,
+		&d.SentAt,
+		&d.DeliveredAt,
+		&d.FailureReason,
+		&d.CreatedAt,
+	)
+	return d, err
+}
+
+func (r *WebhookRepository) ListAttempts(ctx context.Context, notificationID string) ([]*model.WebhookDeliveryAttempt, error) {
+	query := `
+		SELECT a.id, a.notification_id, a.attempt, a.status_code, a.error, a.duration_ms, a.attempted_at
+		FROM webhook_delivery_attempts a
+		WHERE a.notification_id = $1 AND a.tenant_id = $2
+		ORDER BY a.attempt
+	`
+
+	var attempts []*model.WebhookDeliveryAttempt
+	err := inTenant(ctx, r.db, func(q querier, tenantID string) error {
+		rows, err := q.QueryContext(ctx, query, notificationID, tenantID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			a := &model.WebhookDeliveryAttempt{}
+			if err := rows.Scan(
+				&a.ID,
+				&a.NotificationID,
+				&a.Attempt,
+				&a.StatusCode,
+				&a.Error,
+				&a.DurationMs,
+				&a.AttemptedAt,
+			); err != nil {
+				return err
+			}
+			attempts = append(attempts, a)
+		}
+		return rows.Err()
+	})
+
+	return attempts, err
+}
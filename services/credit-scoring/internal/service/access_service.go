@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/authz"
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/model"
+	"credit-scoring/internal/repository"
+	"credit-scoring/pkg/errors"
+	"credit-scoring/pkg/id"
+)
+
+// AccessService decides whether a caller may act on a user's credit data and
+// manages the delegation grants users give each other. Every access to
+// another user's data, allowed or denied, is written to the audit log.
+type AccessService struct {
+	repo   *repository.AccessRepository
+	logger *zap.Logger
+}
+
+func NewAccessService(repo *repository.AccessRepository, logger *zap.Logger) *AccessService {
+	return &AccessService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// AccessRequest is a caller asking to act on UserID's credit data.
+type AccessRequest struct {
+	Principal  authz.Principal
+	Permission authz.Permission
+	UserID     string
+	// Action is the route or gRPC method, for the audit log
+	Action    string
+	RequestID string
+}
+
+// AuthorizeUser returns a Forbidden error unless the caller is the user, is
+// staff, is a partner key bound to a tenant, or holds an active grant from
+// the user that includes the permission. The caller's role must already have
+// been checked for the permission.
+func (s *AccessService) AuthorizeUser(ctx context.Context, req AccessRequest) error {
+	p := req.Principal
+	if p.UserID != "" && p.UserID == req.UserID {
+		return nil
+	}
+
+	if p.IsStaff() {
+		s.audit(ctx, req, authz.BasisStaff, nil)
+		return nil
+	}
+
+	if p.Role == authz.RolePartner && p.TenantID != "" {
+		s.audit(ctx, req, authz.BasisTenant, nil)
+		return nil
+	}
+
+	if p.UserID != "" && authz.Delegable[req.Permission] {
+		grant, err := s.repo.FindActiveGrant(ctx, req.UserID, p.UserID, string(req.Permission))
+		switch {
+		case err == nil:
+			s.audit(ctx, req, authz.BasisDelegation, &grant.ID)
+			return nil
+		case err != repository.ErrNotFound:
+			return err
+		}
+	}
+
+	s.audit(ctx, req, "", nil)
+	return errors.Forbidden("You can only access your own credit data or data delegated to you")
+}
+
+// audit records cross-user access. An empty basis means access was denied.
+// Failing to write the audit log is logged but doesn't fail the request.
+func (s *AccessService) audit(ctx context.Context, req AccessRequest, basis authz.Basis, grantID *string) {
+	fields := []zap.Field{
+		zap.String("actorId", req.Principal.UserID),
+		zap.String("actorRole", req.Principal.Role),
+		zap.String("userId", req.UserID),
+		zap.String("permission", string(req.Permission)),
+		zap.String("action", req.Action),
+		zap.String("request-id", req.RequestID),
+	}
+
+	entry := &model.AccessAuditEntry{
+		ActorID:       req.Principal.UserID,
+		ActorRole:     req.Principal.Role,
+		SubjectUserID: req.UserID,
+		Permission:    string(req.Permission),
+		GrantID:       grantID,
+		Allowed:       basis != "",
+		Action:        req.Action,
+	}
+	if basis != "" {
+		b := string(basis)
+		entry.Basis = &b
+		fields = append(fields, zap.String("basis", b))
+	}
+	if grantID != nil {
+		fields = append(fields, zap.String("grantId", *grantID))
+	}
+	if req.RequestID != "" {
+		entry.RequestID = &req.RequestID
+	}
+
+	if entry.Allowed {
+		s.logger.Info("Cross-user access", fields...)
+	} else {
+		s.logger.Warn("Cross-user access denied", fields...)
+	}
+
+	if err := s.repo.RecordAccess(ctx, entry); err != nil {
+		s.logger.Error("Failed to write access audit log", append(fields, zap.Error(err))...)
+	}
+}
+
+// CreateGrant delegates the requested permissions on ownerID's data to the
+// grantee.
+func (s *AccessService) CreateGrant(ctx context.Context, ownerID string, req *dto.CreateAccessGrantRequest) (*dto.AccessGrant, error) {
+	grant := &model.AccessGrant{
+		ID:          id.New(id.PrefixAccessGrant),
+		OwnerID:     ownerID,
+		GranteeID:   req.GranteeID,
+		Permissions: req.Permissions,
+		ExpiresAt:   req.ExpiresAt,
+	}
+	if err := s.repo.CreateGrant(ctx, grant); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Access grant created",
+		zap.String("grantId", grant.ID),
+		zap.String("ownerId", ownerID),
+		zap.String("granteeId", grant.GranteeID),
+		zap.Strings("permissions", grant.Permissions),
+	)
+
+	return toAccessGrantDTO(grant), nil
+}
+
+func (s *AccessService) ListGrants(ctx context.Context, ownerID string) ([]dto.AccessGrant, error) {
+	grants, err := s.repo.ListGrants(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.AccessGrant, len(grants))
+	for i, grant := range grants {
+		result[i] = *toAccessGrantDTO(grant)
+	}
+	return result, nil
+}
+
+func (s *AccessService) RevokeGrant(ctx context.Context, ownerID, grantID string) error {
+	err := s.repo.RevokeGrant(ctx, ownerID, grantID)
+	if err == repository.ErrNotFound {
+		return errors.NotFound("Access grant not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Access grant revoked", zap.String("grantId", grantID), zap.String("ownerId", ownerID))
+	return nil
+}
+
+func toAccessGrantDTO(grant *model.AccessGrant) *dto.AccessGrant {
+	return &dto.AccessGrant{
+		ID:          grant.ID,
+		OwnerID:     grant.OwnerID,
+		GranteeID:   grant.GranteeID,
+		Permissions: grant.Permissions,
+		ExpiresAt:   grant.ExpiresAt,
+		RevokedAt:   grant.RevokedAt,
+		CreatedAt:   grant.CreatedAt,
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/authz"
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/model"
+	"credit-scoring/internal/repository"
+	"credit-scoring/pkg/errors"
+	"credit-scoring/pkg/id"
+)
+
+// apiKeyPrefix starts every API key so leaked keys are easy to recognise
+const apiKeyPrefix = "csk_"
+
+// displayedKeyLength is how much of a key is stored and shown to tell keys
+// apart: the prefix and 8 random characters
+const displayedKeyLength = len(apiKeyPrefix) + 8
+
+// lastUsedInterval is how stale last_used_at may get, so a busy key isn't
+// written on every request
+const lastUsedInterval = time.Minute
+
+// APIKeyService issues partner API keys and authenticates requests made with
+// them. Each key's quota is enforced by middleware.RateLimit.
+type APIKeyService struct {
+	repo   *repository.APIKeyRepository
+	logger *zap.Logger
+}
+
+func NewAPIKeyService(repo *repository.APIKeyRepository, logger *zap.Logger) *APIKeyService {
+	return &APIKeyService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Authenticate resolves an API key to the partner principal it acts as,
+// carrying the key's quota.
+func (s *APIKeyService) Authenticate(ctx context.Context, raw string) (authz.Principal, error) {
+	key, err := s.repo.GetKeyByHash(ctx, hashToken(raw))
+	if err == repository.ErrNotFound {
+		return authz.Principal{}, errors.Unauthorized("Invalid API key").WithCode("INVALID_API_KEY")
+	}
+	if err != nil {
+		return authz.Principal{}, errors.Unavailable("Unable to check the API key", err)
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !now.Before(*key.ExpiresAt)) {
+		return authz.Principal{}, errors.Unauthorized("API key has been revoked or has expired").WithCode("INVALID_API_KEY")
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > lastUsedInterval {
+		if err := s.repo.TouchKey(ctx, key.ID); err != nil {
+			s.logger.Warn("Failed to record API key use", zap.Error(err), zap.String("keyId", key.ID))
+		}
+	}
+
+	scopes := make([]authz.Scope, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = authz.Scope(scope)
+	}
+
+	return authz.Principal{
+		UserID:    key.ID,
+		Role:      authz.RolePartner,
+		Scopes:    scopes,
+		TenantID:  key.TenantID,
+		RateLimit: key.RateLimit,
+	}, nil
+}
+
+// CreateKey issues a key. The key itself is only in the returned DTO; only
+// its hash is stored.
+func (s *APIKeyService) CreateKey(ctx context.Context, createdBy string, req *dto.CreateAPIKeyRequest) (*dto.APIKey, error) {
+	secret, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	raw := apiKeyPrefix + secret
+
+	rateLimit := req.RateLimit
+	if rateLimit == 0 {
+		rateLimit = dto.DefaultAPIKeyRateLimit
+	}
+
+	key := &model.APIKey{
+		ID:        id.New(id.PrefixAPIKey),
+		Name:      req.Name,
+		TenantID:  req.TenantID,
+		KeyPrefix: raw[:displayedKeyLength],
+		KeyHash:   hashToken(raw),
+		Scopes:    req.Scopes,
+		RateLimit: rateLimit,
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: createdBy,
+	}
+	if err := s.repo.CreateKey(ctx, key); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("API key created",
+		zap.String("keyId", key.ID),
+		zap.String("tenantId", key.TenantID),
+		zap.Strings("scopes", key.Scopes),
+		zap.String("createdBy", createdBy),
+	)
+
+	result := toAPIKeyDTO(key)
+	result.Key = raw
+	return result, nil
+}
+
+// ListKeys lists tenantID's keys, or all keys if it is empty.
+func (s *APIKeyService) ListKeys(ctx context.Context, tenantID string) ([]dto.APIKey, error) {
+	keys, err := s.repo.ListKeys(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.APIKey, len(keys))
+	for i, key := range keys {
+		result[i] = *toAPIKeyDTO(key)
+	}
+	return result, nil
+}
+
+func (s *APIKeyService) RevokeKey(ctx context.Context, keyID string) error {
+	err := s.repo.RevokeKey(ctx, keyID)
+	if err == repository.ErrNotFound {
+		return errors.NotFound("API key not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("API key revoked", zap.String("keyId", keyID))
+	return nil
+}
+
+func toAPIKeyDTO(key *model.APIKey) *dto.APIKey {
+	return &dto.APIKey{
+		ID:         key.ID,
+		Name:       key.Name,
+		TenantID:   key.TenantID,
+		Prefix:     key.KeyPrefix,
+		Scopes:     key.Scopes,
+		RateLimit:  key.RateLimit,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedBy:  key.CreatedBy,
+		CreatedAt:  key.CreatedAt,
+	}
+}
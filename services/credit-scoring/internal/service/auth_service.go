@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"credit-scoring/internal/authz"
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/model"
+	"credit-scoring/internal/repository"
+	"credit-scoring/pkg/errors"
+	"credit-scoring/pkg/id"
+)
+
+// passwordHashCost is the bcrypt cost for user passwords
+const passwordHashCost = 12
+
+// TokenConfig configures the access and refresh tokens AuthService issues.
+// Access tokens are HS256 JWTs signed with Secret, so the service verifies
+// them like any other HS256 token.
+type TokenConfig struct {
+	Secret     string
+	Issuer     string
+	Audience   string
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// AuthStore stores users, API clients and refresh tokens, such as
+// *repository.AuthRepository.
+type AuthStore interface {
+	CreateUser(ctx context.Context, user *model.User) error
+	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	GetUser(ctx context.Context, id string) (*model.User, error)
+	CreateClient(ctx context.Context, client *model.APIClient) error
+	GetClient(ctx context.Context, id string) (*model.APIClient, error)
+	CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*model.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, usedID string, next *model.RefreshToken) error
+	RevokeSession(ctx context.Context, sessionID string) error
+}
+
+// TokenRevoker revokes access tokens before they expire, such as
+// *TokenRevocations.
+type TokenRevoker interface {
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	RevokeSession(ctx context.Context, sid string) error
+}
+
+// AuthService issues tokens to users, who log in with a password and get a
+// rotating refresh token, and to API clients, which use client credentials.
+type AuthService struct {
+	repo        AuthStore
+	revocations TokenRevoker
+	config      TokenConfig
+	// dummyHash is compared against when a login names an unknown user, so
+	// unknown and known emails take as long to reject
+	dummyHash []byte
+	logger    *zap.Logger
+}
+
+func NewAuthService(repo AuthStore, revocations TokenRevoker, config TokenConfig, logger *zap.Logger) (*AuthService, error) {
+	dummyHash, err := bcrypt.GenerateFromPassword([]byte(uuid.NewString()), passwordHashCost)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthService{
+		repo:        repo,
+		revocations: revocations,
+		config:      config,
+		dummyHash:   dummyHash,
+		logger:      logger,
+	}, nil
+}
+
+func invalidCredentials() error {
+	return errors.Unauthorized("Invalid credentials").WithCode("INVALID_CREDENTIALS")
+}
+
+func invalidRefreshToken() error {
+	return errors.Unauthorized("Invalid or expired refresh token").WithCode("INVALID_REFRESH_TOKEN")
+}
+
+// Login exchanges a user's email and password for an access token and a
+// refresh token that starts a new session.
+func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.TokenResponse, error) {
+	if err := s.checkConfigured(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByEmail(ctx, req.Email)
+	if err == repository.ErrNotFound {
+		bcrypt.CompareHashAndPassword(s.dummyHash, []byte(req.Password))
+		return nil, invalidCredentials()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil || !user.Active {
+		s.logger.Info("Login failed", zap.String("userId", user.ID))
+		return nil, invalidCredentials()
+	}
+
+	sessionID := id.New(id.PrefixSession)
+	refreshToken, token, err := s.newRefreshToken(user.ID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateRefreshToken(ctx, token); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("User logged in", zap.String("userId", user.ID), zap.String("sessionId", sessionID))
+
+	return s.tokenResponse(user.ID, user.TenantID, user.Email, user.Role, sessionID, refreshToken)
+}
+
+// ClientToken exchanges client credentials for an access token. Clients get
+// no refresh token; they request a new access token when it expires.
+func (s *AuthService) ClientToken(ctx context.Context, req *dto.ClientTokenRequest) (*dto.TokenResponse, error) {
+	if err := s.checkConfigured(); err != nil {
+		return nil, err
+	}
+
+	client, err := s.repo.GetClient(ctx, req.ClientID)
+	if err == repository.ErrNotFound {
+		return nil, invalidCredentials()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(hashToken(req.ClientSecret))) != 1 || !client.Active {
+		s.logger.Info("Client authentication failed", zap.String("clientId", client.ID))
+		return nil, invalidCredentials()
+	}
+
+	return s.tokenResponse(client.ID, client.TenantID, "", client.Role, "", "")
+}
+
+// Refresh exchanges a refresh token for a new access token and the next
+// refresh token in the session. Presenting a token that was already
+// exchanged means it has leaked, so the whole session is revoked.
+func (s *AuthService) Refresh(ctx context.Context, req *dto.RefreshTokenRequest) (*dto.TokenResponse, error) {
+	if err := s.checkConfigured(); err != nil {
+		return nil, err
+	}
+
+	used, err := s.repo.GetRefreshTokenByHash(ctx, hashToken(req.RefreshToken))
+	if err == repository.ErrNotFound {
+		return nil, invalidRefreshToken()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case used.RevokedAt != nil, !time.Now().Before(used.ExpiresAt):
+		return nil, invalidRefreshToken()
+	case used.UsedAt != nil:
+		return nil, s.revokeReusedSession(ctx, used)
+	}
+
+	user, err := s.repo.GetUser(ctx, used.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Active {
+		if err := s.revokeSession(ctx, used.SessionID); err != nil {
+			return nil, err
+		}
+		return nil, invalidRefreshToken()
+	}
+
+	refreshToken, next, err := s.newRefreshToken(user.ID, used.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RotateRefreshToken(ctx, used.ID, next); err == repository.ErrTokenUsed {
+		// Another request exchanged the same token first
+		return nil, s.revokeReusedSession(ctx, used)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return s.tokenResponse(user.ID, user.TenantID, user.Email, user.Role, used.SessionID, refreshToken)
+}
+
+func (s *AuthService) revokeReusedSession(ctx context.Context, used *model.RefreshToken) error {
+	s.logger.Warn("Refresh token reuse detected; revoking session",
+		zap.String("userId", used.UserID),
+		zap.String("sessionId", used.SessionID),
+		zap.String("tokenId", used.ID),
+	)
+
+	if err := s.revokeSession(ctx, used.SessionID); err != nil {
+		return err
+	}
+	return errors.Unauthorized("Refresh token was already used; the session has been revoked").WithCode("REFRESH_TOKEN_REUSED")
+}
+
+// LogoutRequest identifies the access token being logged out.
+type LogoutRequest struct {
+	TokenID   string
+	SessionID string
+	ExpiresAt time.Time
+}
+
+// Logout revokes the access token and, for user tokens, its whole session
+// including the refresh token.
+func (s *AuthService) Logout(ctx context.Context, req LogoutRequest) error {
+	if req.SessionID != "" {
+		if err := s.revokeSession(ctx, req.SessionID); err != nil {
+			return err
+		}
+	}
+	if req.TokenID != "" {
+		if err := s.revocations.RevokeToken(ctx, req.TokenID, req.ExpiresAt); err != nil {
+			return fmt.Errorf("failed to revoke access token: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *AuthService) revokeSession(ctx context.Context, sessionID string) error {
+	if err := s.repo.RevokeSession(ctx, sessionID); err != nil {
+		return err
+	}
+	if err := s.revocations.RevokeSession(ctx, sessionID); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// CreateUser adds a user of tenantID who can log in with password.
+func (s *AuthService) CreateUser(ctx context.Context, userID, tenantID, email, password, role string) (*model.User, error) {
+	if !authz.KnownRole(role) {
+		return nil, fmt.Errorf("unknown role %q", role)
+	}
+	if userID == "" {
+		userID = id.New(id.PrefixUser)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordHashCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.User{
+		ID:           userID,
+		TenantID:     tenantID,
+		Email:        email,
+		PasswordHash: string(hash),
+		Role:         role,
+		Active:       true,
+	}
+	if err := s.repo.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// CreateClient adds an API client of tenantID and returns it with its
+// secret, which is not stored and can't be shown again.
+func (s *AuthService) CreateClient(ctx context.Context, tenantID, name, role string) (*model.APIClient, string, error) {
+	if !authz.KnownRole(role) {
+		return nil, "", fmt.Errorf("unknown role %q", role)
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := &model.APIClient{
+		ID:         id.New(id.PrefixAPIClient),
+		TenantID:   tenantID,
+		Name:       name,
+		SecretHash: hashToken(secret),
+		Role:       role,
+		Active:     true,
+	}
+	if err := s.repo.CreateClient(ctx, client); err != nil {
+		return nil, "", err
+	}
+	return client, secret, nil
+}
+
+func (s *AuthService) checkConfigured() error {
+	if s.config.Secret == "" {
+		return errors.Unavailable("Token issuance is not configured", nil)
+	}
+	return nil
+}
+
+func (s *AuthService) newRefreshToken(userID, sessionID string) (string, *model.RefreshToken, error) {
+	raw, err := randomToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return raw, &model.RefreshToken{
+		ID:        id.New(id.PrefixRefreshToken),
+		SessionID: sessionID,
+		UserID:    userID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(s.config.RefreshTTL),
+	}, nil
+}
+
+// tokenResponse signs an access token for the subject acting for tenantID.
+// sessionID and refreshToken are empty for clients.
+func (s *AuthService) tokenResponse(subject, tenantID, email, role, sessionID, refreshToken string) (*dto.TokenResponse, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub":      subject,
+		"userId":   subject,
+		"tenantId": tenantID,
+		"role":     role,
+		"jti":      uuid.NewString(),
+		"iat":      now.Unix(),
+		"nbf":      now.Unix(),
+		"exp":      now.Add(s.config.AccessTTL).Unix(),
+	}
+	if email != "" {
+		claims["email"] = email
+	}
+	if sessionID != "" {
+		claims["sid"] = sessionID
+	}
+	if s.config.Issuer != "" {
+		claims["iss"] = s.config.Issuer
+	}
+	if s.config.Audience != "" {
+		claims["aud"] = s.config.Audience
+	}
+
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.config.Secret))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.config.AccessTTL.Seconds()),
+	}, nil
+}
+
+// randomToken returns 256 random bits, base64url encoded
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is the hex SHA-256 stored in place of secrets and refresh
+// tokens. They are random, so a fast hash is enough.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,411 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/middleware"
+	"credit-scoring/internal/model"
+	"credit-scoring/internal/repository"
+	"credit-scoring/pkg/errors"
+)
+
+const testSecret = "test-secret"
+
+// fakeAuthStore keeps users and refresh tokens in memory, with the same
+// rotation semantics as the repository.
+type fakeAuthStore struct {
+	mu     sync.Mutex
+	users  map[string]*model.User
+	tokens map[string]*model.RefreshToken
+	// beforeRotate runs before a rotation takes effect, to simulate another
+	// request rotating the same token first
+	beforeRotate func(usedID string)
+}
+
+func newFakeAuthStore() *fakeAuthStore {
+	return &fakeAuthStore{users: map[string]*model.User{}, tokens: map[string]*model.RefreshToken{}}
+}
+
+func (f *fakeAuthStore) CreateUser(ctx context.Context, user *model.User) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.users[user.ID] = user
+	return nil
+}
+
+func (f *fakeAuthStore) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, u := range f.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeAuthStore) GetUser(ctx context.Context, id string) (*model.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if u, ok := f.users[id]; ok {
+		return u, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeAuthStore) CreateClient(ctx context.Context, client *model.APIClient) error {
+	return nil
+}
+
+func (f *fakeAuthStore) GetClient(ctx context.Context, id string) (*model.APIClient, error) {
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeAuthStore) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens[token.ID] = token
+	return nil
+}
+
+func (f *fakeAuthStore) GetRefreshTokenByHash(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, t := range f.tokens {
+		if t.TokenHash == hash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeAuthStore) RotateRefreshToken(ctx context.Context, usedID string, next *model.RefreshToken) error {
+	if f.beforeRotate != nil {
+		f.beforeRotate(usedID)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	used := f.tokens[usedID]
+	if used.UsedAt != nil || used.RevokedAt != nil {
+		return repository.ErrTokenUsed
+	}
+	now := time.Now()
+	used.UsedAt = &now
+	f.tokens[next.ID] = next
+	return nil
+}
+
+func (f *fakeAuthStore) RevokeSession(ctx context.Context, sessionID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	for _, t := range f.tokens {
+		if t.SessionID == sessionID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// sessionRevoked reports whether every refresh token in the session is revoked.
+func (f *fakeAuthStore) sessionRevoked(sessionID string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	found := false
+	for _, t := range f.tokens {
+		if t.SessionID == sessionID {
+			found = true
+			if t.RevokedAt == nil {
+				return false
+			}
+		}
+	}
+	return found
+}
+
+// fakeRevocations is an in-memory revocation list, used both by the service
+// and by the verifier checking its access tokens.
+type fakeRevocations struct {
+	mu       sync.Mutex
+	tokens   map[string]bool
+	sessions map[string]bool
+}
+
+func newFakeRevocations() *fakeRevocations {
+	return &fakeRevocations{tokens: map[string]bool{}, sessions: map[string]bool{}}
+}
+
+func (f *fakeRevocations) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens[jti] = true
+	return nil
+}
+
+func (f *fakeRevocations) RevokeSession(ctx context.Context, sid string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessions[sid] = true
+	return nil
+}
+
+func (f *fakeRevocations) IsRevoked(ctx context.Context, jti, sid string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tokens[jti] || f.sessions[sid], nil
+}
+
+type authFixture struct {
+	service     *AuthService
+	store       *fakeAuthStore
+	revocations *fakeRevocations
+	verifier    *middleware.TokenVerifier
+}
+
+func newAuthFixture(t *testing.T, secret string) *authFixture {
+	t.Helper()
+
+	f := &authFixture{store: newFakeAuthStore(), revocations: newFakeRevocations()}
+	var err error
+	f.service, err = NewAuthService(f.store, f.revocations, TokenConfig{
+		Secret:     secret,
+		AccessTTL:  15 * time.Minute,
+		RefreshTTL: 24 * time.Hour,
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.verifier = middleware.NewTokenVerifier(middleware.TokenVerifierConfig{Secret: secret, Revocations: f.revocations})
+
+	for _, u := range []struct {
+		id, email string
+		active    bool
+	}{
+		{"us_active", "ada@example.com", true},
+		{"us_inactive", "grace@example.com", false},
+	} {
+		hash, _ := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+		f.store.CreateUser(context.Background(), &model.User{
+			ID: u.id, TenantID: "tn_1", Email: u.email, PasswordHash: string(hash), Role: "customer", Active: u.active,
+		})
+	}
+	return f
+}
+
+func (f *authFixture) login(t *testing.T) *dto.TokenResponse {
+	t.Helper()
+	tokens, err := f.service.Login(context.Background(), &dto.LoginRequest{Email: "ada@example.com", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return tokens
+}
+
+func (f *authFixture) refresh(refreshToken string) (*dto.TokenResponse, error) {
+	return f.service.Refresh(context.Background(), &dto.RefreshTokenRequest{RefreshToken: refreshToken})
+}
+
+// sessionID returns the sid claim of an access token the verifier accepts.
+func (f *authFixture) sessionID(t *testing.T, accessToken string) string {
+	t.Helper()
+	claims, err := f.verifier.ParseBearerToken(context.Background(), "Bearer "+accessToken)
+	if err != nil {
+		t.Fatalf("access token rejected: %v", err)
+	}
+	sid, _ := claims["sid"].(string)
+	return sid
+}
+
+func assertCode(t *testing.T, err error, code string) {
+	t.Helper()
+	e, ok := errors.As(err)
+	if !ok || e.Code != code {
+		t.Fatalf("error = %v, want code %s", err, code)
+	}
+}
+
+func TestAuthServiceLogin(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		email    string
+		password string
+		wantCode string
+	}{
+		{name: "valid credentials", secret: testSecret, email: "ada@example.com", password: "correct horse"},
+		{name: "wrong password", secret: testSecret, email: "ada@example.com", password: "wrong", wantCode: "INVALID_CREDENTIALS"},
+		{name: "unknown user", secret: testSecret, email: "nobody@example.com", password: "correct horse", wantCode: "INVALID_CREDENTIALS"},
+		{name: "inactive user", secret: testSecret, email: "grace@example.com", password: "correct horse", wantCode: "INVALID_CREDENTIALS"},
+		{name: "not configured", email: "ada@example.com", password: "correct horse", wantCode: "SERVICE_UNAVAILABLE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuthFixture(t, tt.secret)
+
+			tokens, err := f.service.Login(context.Background(), &dto.LoginRequest{Email: tt.email, Password: tt.password})
+			if tt.wantCode != "" {
+				assertCode(t, err, tt.wantCode)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Login: %v", err)
+			}
+
+			claims, err := f.verifier.ParseBearerToken(context.Background(), "Bearer "+tokens.AccessToken)
+			if err != nil {
+				t.Fatalf("access token rejected: %v", err)
+			}
+			if claims["sub"] != "us_active" || claims["tenantId"] != "tn_1" || claims["sid"] == nil {
+				t.Errorf("unexpected claims %v", claims)
+			}
+			if tokens.RefreshToken == "" {
+				t.Error("no refresh token issued")
+			}
+		})
+	}
+}
+
+func TestAuthServiceRefresh(t *testing.T) {
+	tests := []struct {
+		name string
+		// run exchanges refresh tokens from a fresh login and checks the
+		// outcome
+		run func(t *testing.T, f *authFixture, login *dto.TokenResponse)
+	}{
+		{
+			name: "rotation issues a new refresh token",
+			run: func(t *testing.T, f *authFixture, login *dto.TokenResponse) {
+				next, err := f.refresh(login.RefreshToken)
+				if err != nil {
+					t.Fatalf("Refresh: %v", err)
+				}
+				if next.RefreshToken == login.RefreshToken {
+					t.Error("refresh token was not rotated")
+				}
+				if f.sessionID(t, next.AccessToken) != f.sessionID(t, login.AccessToken) {
+					t.Error("rotation started a new session")
+				}
+				if _, err := f.refresh(next.RefreshToken); err != nil {
+					t.Errorf("rotated token rejected: %v", err)
+				}
+			},
+		},
+		{
+			name: "reuse revokes the session",
+			run: func(t *testing.T, f *authFixture, login *dto.TokenResponse) {
+				sid := f.sessionID(t, login.AccessToken)
+				next, err := f.refresh(login.RefreshToken)
+				if err != nil {
+					t.Fatalf("Refresh: %v", err)
+				}
+
+				_, err = f.refresh(login.RefreshToken)
+				assertCode(t, err, "REFRESH_TOKEN_REUSED")
+
+				if !f.store.sessionRevoked(sid) {
+					t.Error("session's refresh tokens not revoked")
+				}
+				if _, err := f.verifier.ParseBearerToken(context.Background(), "Bearer "+next.AccessToken); err == nil {
+					t.Error("session's access token still accepted")
+				}
+				_, err = f.refresh(next.RefreshToken)
+				assertCode(t, err, "INVALID_REFRESH_TOKEN")
+			},
+		},
+		{
+			name: "concurrent rotation revokes the session",
+			run: func(t *testing.T, f *authFixture, login *dto.TokenResponse) {
+				sid := f.sessionID(t, login.AccessToken)
+				// Another request exchanges the token between the lookup
+				// and the rotation
+				f.store.beforeRotate = func(usedID string) {
+					f.store.mu.Lock()
+					defer f.store.mu.Unlock()
+					now := time.Now()
+					f.store.tokens[usedID].UsedAt = &now
+				}
+
+				_, err := f.refresh(login.RefreshToken)
+				assertCode(t, err, "REFRESH_TOKEN_REUSED")
+				if !f.store.sessionRevoked(sid) || !f.revocations.sessions[sid] {
+					t.Error("session not revoked")
+				}
+			},
+		},
+		{
+			name: "unknown token",
+			run: func(t *testing.T, f *authFixture, login *dto.TokenResponse) {
+				_, err := f.refresh("not-a-token")
+				assertCode(t, err, "INVALID_REFRESH_TOKEN")
+			},
+		},
+		{
+			name: "expired token",
+			run: func(t *testing.T, f *authFixture, login *dto.TokenResponse) {
+				for _, token := range f.store.tokens {
+					token.ExpiresAt = time.Now().Add(-time.Minute)
+				}
+				_, err := f.refresh(login.RefreshToken)
+				assertCode(t, err, "INVALID_REFRESH_TOKEN")
+			},
+		},
+		{
+			name: "deactivated user",
+			run: func(t *testing.T, f *authFixture, login *dto.TokenResponse) {
+				sid := f.sessionID(t, login.AccessToken)
+				f.store.users["us_active"].Active = false
+
+				_, err := f.refresh(login.RefreshToken)
+				assertCode(t, err, "INVALID_REFRESH_TOKEN")
+				if !f.store.sessionRevoked(sid) {
+					t.Error("session not revoked")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuthFixture(t, testSecret)
+			tt.run(t, f, f.login(t))
+		})
+	}
+}
+
+func TestAuthServiceLogout(t *testing.T) {
+	f := newAuthFixture(t, testSecret)
+	login := f.login(t)
+
+	claims, err := f.verifier.ParseBearerToken(context.Background(), "Bearer "+login.AccessToken)
+	if err != nil {
+		t.Fatalf("access token rejected: %v", err)
+	}
+	exp, _ := claims.GetExpirationTime()
+
+	if err := f.service.Logout(context.Background(), LogoutRequest{
+		TokenID:   claims["jti"].(string),
+		SessionID: claims["sid"].(string),
+		ExpiresAt: exp.Time,
+	}); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+
+	if _, err := f.verifier.ParseBearerToken(context.Background(), "Bearer "+login.AccessToken); err == nil {
+		t.Error("access token still accepted after logout")
+	}
+	if !f.revocations.tokens[claims["jti"].(string)] {
+		t.Error("access token not revoked")
+	}
+	_, err = f.refresh(login.RefreshToken)
+	assertCode(t, err, "INVALID_REFRESH_TOKEN")
+}
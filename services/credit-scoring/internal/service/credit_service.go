@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	"go.uber.org/zap"
@@ -12,34 +13,79 @@ import (
 	"credit-scoring/internal/dto"
 	"credit-scoring/internal/model"
 	"credit-scoring/internal/repository"
+	"credit-scoring/internal/tenant"
+	"credit-scoring/pkg/errors"
+	"credit-scoring/pkg/id"
 	"credit-scoring/pkg/kafka"
 	"credit-scoring/pkg/redis"
 )
 
+// defaultHistoryLimit is the history page size when the client doesn't set one
+const defaultHistoryLimit = 12
+
+// scoreModelVersion identifies the built-in scoring model. Bump it whenever
+// its weights, or the bands or reason codes below, change.
+const scoreModelVersion = "cs-1.0"
+
+// scoreCacheKey is where a user's latest model.CreditScore is cached
+func scoreCacheKey(tenantID, userID string) string {
+	return fmt.Sprintf("credit_score:v2:%s:%s", tenantID, userID)
+}
+
+// CreditScoringService calculates and serves the scores of the tenant in the
+// request context, with that tenant's scoring model.
 type CreditScoringService struct {
 	repo     *repository.CreditRepository
+	tenants  *TenantService
 	cache    *redis.RedisClient
 	producer *kafka.Producer
+	webhooks *WebhookService
 	logger   *zap.Logger
 }
 
 func NewCreditScoringService(
 	repo *repository.CreditRepository,
+	tenants *TenantService,
 	cache *redis.RedisClient,
 	producer *kafka.Producer,
+	webhooks *WebhookService,
 	logger *zap.Logger,
 ) *CreditScoringService {
 	return &CreditScoringService{
 		repo:     repo,
+		tenants:  tenants,
 		cache:    cache,
 		producer: producer,
+		webhooks: webhooks,
 		logger:   logger,
 	}
 }
 
 // CalculateScore calculates credit score using proprietary algorithm
 func (s *CreditScoringService) CalculateScore(ctx context.Context, req *dto.CalculateScoreRequest) (*dto.CreditScore, error) {
-	s.logger.Info("Calculating credit score", zap.String("userId", req.UserID))
+	score, err := s.calculate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return toCreditScore(score), nil
+}
+
+func (s *CreditScoringService) calculate(ctx context.Context, req *dto.CalculateScoreRequest) (*model.CreditScore, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scoringModel, err := s.tenants.ScoringModel(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	weights := scoringModel.Weights
+
+	s.logger.Info("Calculating credit score",
+		zap.String("tenantId", tenantID),
+		zap.String("userId", req.UserID),
+		zap.String("modelVersion", scoringModel.Version),
+	)
 
 	// Calculate base score components
 	incomeScore := s.calculateIncomeScore(req.IncomeAmount)
@@ -49,10 +95,10 @@ func (s *CreditScoringService) CalculateScore(ctx context.Context, req *dto.Calc
 
 	// Weighted average
 	totalScore := int(
-		incomeScore*0.30 +
-			employmentScore*0.25 +
-			accountAgeScore*0.20 +
-			loanHistoryScore*0.25,
+		incomeScore*weights.Income +
+			employmentScore*weights.Employment +
+			accountAgeScore*weights.AccountAge +
+			loanHistoryScore*weights.LoanHistory,
 	)
 
 	// Ensure score is in valid range (300-850)
@@ -63,131 +109,283 @@ func (s *CreditScoringService) CalculateScore(ctx context.Context, req *dto.Calc
 		totalScore = 850
 	}
 
+	subScores := []model.SubScore{
+		{Name: dto.SubScoreIncome, Score: int(math.Round(incomeScore)), Weight: weights.Income},
+		{Name: dto.SubScoreEmployment, Score: int(math.Round(employmentScore)), Weight: weights.Employment},
+		{Name: dto.SubScoreAccountAge, Score: int(math.Round(accountAgeScore)), Weight: weights.AccountAge},
+		{Name: dto.SubScoreLoanHistory, Score: int(math.Round(loanHistoryScore)), Weight: weights.LoanHistory},
+	}
+
 	// Determine grade
 	grade := s.getGrade(totalScore)
-	
+
 	// Generate factors and recommendation
 	factors := s.generateFactors(req, totalScore)
 	recommendation := s.generateRecommendation(totalScore)
 
-	creditScore := &dto.CreditScore{
-		ID:             generateID(),
+	creditScore := &model.CreditScore{
+		ID:             id.New(id.PrefixCreditScore),
 		UserID:         req.UserID,
 		Score:          totalScore,
 		Grade:          grade,
 		Factors:        factors,
 		Recommendation: recommendation,
+		ModelVersion:   scoringModel.Version,
+		SubScores:      subScores,
+		ReasonCodes:    s.generateReasonCodes(req, subScores),
 		CalculatedAt:   time.Now(),
-		ExpiresAt:      time.Now().Add(30 * 24 * time.Hour),
+		ExpiresAt:      time.Now().Add(time.Duration(scoringModel.ValidityDays) * 24 * time.Hour),
 	}
 
 	// Save to database
-	dbModel := &model.CreditScore{
-		ID:             creditScore.ID,
-		UserID:         creditScore.UserID,
-		Score:          creditScore.Score,
-		Grade:          creditScore.Grade,
-		Factors:        creditScore.Factors,
-		Recommendation: creditScore.Recommendation,
-		CalculatedAt:   creditScore.CalculatedAt,
-		ExpiresAt:      creditScore.ExpiresAt,
-	}
-
-	if err := s.repo.Create(ctx, dbModel); err != nil {
+	if err := s.repo.Create(ctx, creditScore); err != nil {
 		s.logger.Error("Failed to save credit score", zap.Error(err))
 		return nil, err
 	}
 
+	// Keep the inputs so the score can be recalculated from upstream events
+	if err := s.saveInputs(ctx, req); err != nil {
+		s.logger.Warn("Failed to save scoring inputs", zap.Error(err))
+	}
+
 	// Cache the result
-	cacheKey := fmt.Sprintf("credit_score:%s", req.UserID)
-	if err := s.cache.Set(ctx, cacheKey, creditScore, 15*time.Minute); err != nil {
+	if err := s.cache.Set(ctx, scoreCacheKey(tenantID, req.UserID), creditScore, 15*time.Minute); err != nil {
 		s.logger.Warn("Failed to cache credit score", zap.Error(err))
 	}
 
 	// Publish event to Kafka
-	event := map[string]interface{}{
-		"eventType":  "credit_score_calculated",
-		"userId":     req.UserID,
-		"score":      totalScore,
-		"grade":      grade,
-		"timestamp":  time.Now(),
-	}
-	eventJSON, _ := json.Marshal(event)
-	if err := s.producer.Publish(ctx, "credit-scoring-events", eventJSON); err != nil {
+	event := &kafka.CreditScoreEvent{
+		EventType: "credit_score_calculated",
+		TenantID:  tenantID,
+		UserID:    req.UserID,
+		Score:     totalScore,
+		Grade:     grade,
+		Timestamp: time.Now(),
+	}
+	if err := s.producer.PublishEvent(ctx, "credit-scoring-events", req.UserID, event); err != nil {
 		s.logger.Warn("Failed to publish event", zap.Error(err))
 	}
 
+	s.publishScoreUpdate(ctx, toCreditScore(creditScore))
+	s.enqueueWebhooks(ctx, dto.EventScoreCalculated, toCreditScore(creditScore))
+
 	return creditScore, nil
 }
 
 // GetScore retrieves the current credit score from cache or database
 func (s *CreditScoringService) GetScore(ctx context.Context, userID string) (*dto.CreditScore, error) {
+	score, err := s.latestScore(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toCreditScore(score), nil
+}
+
+func (s *CreditScoringService) latestScore(ctx context.Context, userID string) (*model.CreditScore, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Try cache first
-	cacheKey := fmt.Sprintf("credit_score:%s", userID)
-	var cachedScore dto.CreditScore
+	cacheKey := scoreCacheKey(tenantID, userID)
+	var cachedScore model.CreditScore
 	if err := s.cache.Get(ctx, cacheKey, &cachedScore); err == nil {
 		return &cachedScore, nil
 	}
 
 	// Fetch from database
-	dbScore, err := s.repo.GetLatestByUserID(ctx, userID)
+	score, err := s.repo.GetLatestByUserID(ctx, userID)
+	if err == repository.ErrNotFound {
+		return nil, errors.NotFound("Credit score not found")
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	score := &dto.CreditScore{
-		ID:             dbScore.ID,
-		UserID:         dbScore.UserID,
-		Score:          dbScore.Score,
-		Grade:          dbScore.Grade,
-		Factors:        dbScore.Factors,
-		Recommendation: dbScore.Recommendation,
-		CalculatedAt:   dbScore.CalculatedAt,
-		ExpiresAt:      dbScore.ExpiresAt,
-	}
-
 	// Cache for next time
 	s.cache.Set(ctx, cacheKey, score, 15*time.Minute)
 
 	return score, nil
 }
 
-// GetHistory retrieves credit score history
-func (s *CreditScoringService) GetHistory(ctx context.Context, userID string) (*dto.CreditScoreHistory, error) {
-	dbScores, err := s.repo.GetHistoryByUserID(ctx, userID, 12) // Last 12 scores
+// GetHistory retrieves a page of credit score history, newest first
+func (s *CreditScoringService) GetHistory(ctx context.Context, userID string, query *dto.HistoryQuery) (*dto.CreditScoreHistory, error) {
+	scores, nextCursor, err := s.historyPage(ctx, userID, query)
 	if err != nil {
 		return nil, err
 	}
 
-	history := make([]dto.CreditScore, len(dbScores))
-	for i, dbScore := range dbScores {
-		history[i] = dto.CreditScore{
-			ID:             dbScore.ID,
-			UserID:         dbScore.UserID,
-			Score:          dbScore.Score,
-			Grade:          dbScore.Grade,
-			Factors:        dbScore.Factors,
-			Recommendation: dbScore.Recommendation,
-			CalculatedAt:   dbScore.CalculatedAt,
-			ExpiresAt:      dbScore.ExpiresAt,
-		}
+	history := make([]dto.CreditScore, len(scores))
+	for i, score := range scores {
+		history[i] = *toCreditScore(score)
 	}
 
 	return &dto.CreditScoreHistory{
-		UserID:  userID,
-		History: history,
+		UserID:     userID,
+		History:    history,
+		NextCursor: nextCursor,
 	}, nil
 }
 
+func (s *CreditScoringService) historyPage(ctx context.Context, userID string, query *dto.HistoryQuery) ([]*model.CreditScore, string, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultHistoryLimit
+	}
+
+	filter := repository.HistoryFilter{
+		UserID: userID,
+		From:   query.From,
+		Grade:  query.Grade,
+		// Fetch one extra row to find out whether another page exists
+		Limit: limit + 1,
+	}
+	if !query.To.IsZero() {
+		// to is an inclusive date
+		filter.To = query.To.AddDate(0, 0, 1)
+	}
+	if query.Cursor != "" {
+		cursor, err := dto.DecodeHistoryCursor(query.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		filter.AfterCalculatedAt = cursor.CalculatedAt
+		filter.AfterID = cursor.ID
+	}
+
+	scores, err := s.repo.GetHistory(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(scores) > limit {
+		scores = scores[:limit]
+		last := scores[limit-1]
+		nextCursor = dto.HistoryCursor{CalculatedAt: last.CalculatedAt, ID: last.ID}.Encode()
+	}
+
+	return scores, nextCursor, nil
+}
+
 // RefreshScore forces recalculation of credit score
 func (s *CreditScoringService) RefreshScore(ctx context.Context, userID string) (*dto.CreditScore, error) {
+	score, err := s.refresh(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toCreditScore(score), nil
+}
+
+func (s *CreditScoringService) refresh(ctx context.Context, userID string) (*model.CreditScore, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Invalidate cache
-	cacheKey := fmt.Sprintf("credit_score:%s", userID)
-	s.cache.Delete(ctx, cacheKey)
+	s.cache.Delete(ctx, scoreCacheKey(tenantID, userID))
 
 	// In production, you'd fetch fresh data and recalculate
 	// For now, return the latest score
-	return s.GetScore(ctx, userID)
+	score, err := s.latestScore(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishScoreUpdate(ctx, toCreditScore(score))
+	s.enqueueWebhooks(ctx, dto.EventScoreRefreshed, toCreditScore(score))
+
+	return score, nil
+}
+
+// RecalculateScore applies an upstream event to the user's stored scoring
+// inputs and calculates a new score. Events are processed at most once per
+// tenant and eventID; a nil score with a nil error means the event was a
+// duplicate.
+func (s *CreditScoringService) RecalculateScore(ctx context.Context, eventID, userID string, apply func(req *dto.CalculateScoreRequest)) (*dto.CreditScore, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claimKey := fmt.Sprintf("processed_event:%s:%s", tenantID, eventID)
+	claimed, err := s.cache.SetNX(ctx, claimKey, userID, 7*24*time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim event: %w", err)
+	}
+	if !claimed {
+		s.logger.Info("Skipping duplicate event", zap.String("eventId", eventID), zap.String("userId", userID))
+		return nil, nil
+	}
+
+	score, err := s.recalculate(ctx, userID, apply)
+	if err != nil {
+		// Release the claim so a retry can process the event
+		s.cache.Delete(ctx, claimKey)
+		return nil, err
+	}
+
+	return score, nil
+}
+
+func (s *CreditScoringService) recalculate(ctx context.Context, userID string, apply func(req *dto.CalculateScoreRequest)) (*dto.CreditScore, error) {
+	inputs, err := s.repo.GetInputsByUserID(ctx, userID)
+	if err == repository.ErrNotFound {
+		return nil, errors.NotFound(fmt.Sprintf("No scoring inputs for user %s", userID))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	req := &dto.CalculateScoreRequest{
+		UserID:           inputs.UserID,
+		IncomeAmount:     inputs.IncomeAmount,
+		EmploymentStatus: inputs.EmploymentStatus,
+		AccountAge:       inputs.AccountAge + monthsSince(inputs.UpdatedAt),
+	}
+	if err := json.Unmarshal(inputs.TransactionData, &req.TransactionData); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction data: %w", err)
+	}
+	if err := json.Unmarshal(inputs.LoanHistory, &req.LoanHistory); err != nil {
+		return nil, fmt.Errorf("failed to decode loan history: %w", err)
+	}
+
+	apply(req)
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.CalculateScore(ctx, req)
+}
+
+func (s *CreditScoringService) saveInputs(ctx context.Context, req *dto.CalculateScoreRequest) error {
+	transactionData, err := json.Marshal(req.TransactionData)
+	if err != nil {
+		return err
+	}
+	if req.TransactionData == nil {
+		transactionData = []byte("{}")
+	}
+
+	loanHistory, err := json.Marshal(req.LoanHistory)
+	if err != nil {
+		return err
+	}
+	if req.LoanHistory == nil {
+		loanHistory = []byte("[]")
+	}
+
+	return s.repo.UpsertInputs(ctx, &model.CreditScoreInputs{
+		UserID:           req.UserID,
+		IncomeAmount:     req.IncomeAmount,
+		EmploymentStatus: req.EmploymentStatus,
+		AccountAge:       req.AccountAge,
+		TransactionData:  transactionData,
+		LoanHistory:      loanHistory,
+	})
 }
 
 // Helper functions for score calculation
@@ -249,7 +447,7 @@ func (s *CreditScoringService) getGrade(score int) string {
 
 func (s *CreditScoringService) generateFactors(req *dto.CalculateScoreRequest, score int) []string {
 	factors := []string{}
-	
+
 	if req.IncomeAmount < 100000 {
 		factors = append(factors, "Low income level")
 	}
@@ -263,7 +461,7 @@ func (s *CreditScoringService) generateFactors(req *dto.CalculateScoreRequest, s
 		factors = append(factors, "Strong payment history")
 		factors = append(factors, "Good financial stability")
 	}
-	
+
 	return factors
 }
 
@@ -278,6 +476,63 @@ func (s *CreditScoringService) generateRecommendation(score int) string {
 	return "Credit profile needs improvement. Consider secured products."
 }
 
-func generateID() string {
-	return fmt.Sprintf("cs_%d", time.Now().UnixNano())
+// reasonCodeThreshold is the sub-score below which a component is reported
+// as a reason the score isn't higher
+const reasonCodeThreshold = 670
+
+// maxReasonCodes follows the usual adverse action notice limit
+const maxReasonCodes = 4
+
+// generateReasonCodes returns reason codes for the weak components of a
+// score, the ones costing the most points first.
+func (s *CreditScoringService) generateReasonCodes(req *dto.CalculateScoreRequest, subScores []model.SubScore) []string {
+	weak := make([]model.SubScore, 0, len(subScores))
+	for _, sub := range subScores {
+		if sub.Score < reasonCodeThreshold {
+			weak = append(weak, sub)
+		}
+	}
+	sort.SliceStable(weak, func(i, j int) bool {
+		return float64(850-weak[i].Score)*weak[i].Weight > float64(850-weak[j].Score)*weak[j].Weight
+	})
+
+	codes := []string{}
+	for _, sub := range weak {
+		switch sub.Name {
+		case dto.SubScoreIncome:
+			codes = append(codes, dto.ReasonLowIncome)
+		case dto.SubScoreEmployment:
+			codes = append(codes, dto.ReasonEmploymentStatus)
+		case dto.SubScoreAccountAge:
+			codes = append(codes, dto.ReasonShortAccountHistory)
+		case dto.SubScoreLoanHistory:
+			if len(req.LoanHistory) == 0 {
+				codes = append(codes, dto.ReasonNoLoanHistory)
+			} else {
+				codes = append(codes, dto.ReasonMissedPayments)
+			}
+		}
+		if len(codes) == maxReasonCodes {
+			break
+		}
+	}
+
+	return codes
+}
+
+func toCreditScore(score *model.CreditScore) *dto.CreditScore {
+	return &dto.CreditScore{
+		ID:             score.ID,
+		UserID:         score.UserID,
+		Score:          score.Score,
+		Grade:          score.Grade,
+		Factors:        score.Factors,
+		Recommendation: score.Recommendation,
+		CalculatedAt:   score.CalculatedAt,
+		ExpiresAt:      score.ExpiresAt,
+	}
+}
+
+func monthsSince(t time.Time) int {
+	return int(time.Since(t).Hours() / (24 * 30))
 }
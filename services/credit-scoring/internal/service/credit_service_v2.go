@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/model"
+)
+
+// The probability of default follows a standard scorecard scale: good:bad
+// odds of pdBaseOdds at pdBaseScore, doubling every pdPointsToDouble points.
+const (
+	pdBaseScore      = 600
+	pdBaseOdds       = 50.0
+	pdPointsToDouble = 20.0
+)
+
+// CalculateScoreV2 calculates a credit score and returns it with its
+// breakdown.
+func (s *CreditScoringService) CalculateScoreV2(ctx context.Context, req *dto.CalculateScoreRequest) (*dto.CreditScoreV2, error) {
+	score, err := s.calculate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return toCreditScoreV2(score), nil
+}
+
+func (s *CreditScoringService) GetScoreV2(ctx context.Context, userID string) (*dto.CreditScoreV2, error) {
+	score, err := s.latestScore(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toCreditScoreV2(score), nil
+}
+
+func (s *CreditScoringService) GetHistoryV2(ctx context.Context, userID string, query *dto.HistoryQuery) (*dto.CreditScoreHistoryV2, error) {
+	scores, nextCursor, err := s.historyPage(ctx, userID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]dto.CreditScoreV2, len(scores))
+	for i, score := range scores {
+		history[i] = *toCreditScoreV2(score)
+	}
+
+	return &dto.CreditScoreHistoryV2{
+		UserID:     userID,
+		History:    history,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func (s *CreditScoringService) RefreshScoreV2(ctx context.Context, userID string) (*dto.CreditScoreV2, error) {
+	score, err := s.refresh(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toCreditScoreV2(score), nil
+}
+
+func toCreditScoreV2(score *model.CreditScore) *dto.CreditScoreV2 {
+	subScores := make([]dto.SubScore, len(score.SubScores))
+	for i, sub := range score.SubScores {
+		subScores[i] = dto.SubScore{Name: sub.Name, Score: sub.Score, Weight: sub.Weight}
+	}
+
+	reasons := make([]dto.ReasonCode, len(score.ReasonCodes))
+	for i, code := range score.ReasonCodes {
+		reasons[i] = dto.ReasonCode{Code: code, Description: dto.ReasonDescriptions[code]}
+	}
+
+	factors := score.Factors
+	if factors == nil {
+		factors = []string{}
+	}
+
+	return &dto.CreditScoreV2{
+		ID:                   score.ID,
+		UserID:               score.UserID,
+		Score:                score.Score,
+		Grade:                score.Grade,
+		ModelVersion:         score.ModelVersion,
+		ProbabilityOfDefault: probabilityOfDefault(score.Score),
+		SubScores:            subScores,
+		ReasonCodes:          reasons,
+		Factors:              factors,
+		Recommendation:       score.Recommendation,
+		CalculatedAt:         score.CalculatedAt.UTC(),
+		ExpiresAt:            score.ExpiresAt.UTC(),
+	}
+}
+
+// probabilityOfDefault maps a score to a 12-month probability of default,
+// rounded to six decimal places.
+func probabilityOfDefault(score int) float64 {
+	odds := pdBaseOdds * math.Pow(2, float64(score-pdBaseScore)/pdPointsToDouble)
+	return math.Round(1/(1+odds)*1e6) / 1e6
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/model"
+	"credit-scoring/pkg/errors"
+)
+
+// maxTrendScores bounds how many scores a trend is computed from
+const maxTrendScores = 10000
+
+// GetTrend summarizes a user's score history into monthly or quarterly buckets
+// along with grade transitions and factor changes between consecutive scores
+func (s *CreditScoringService) GetTrend(ctx context.Context, userID string, query *dto.TrendQuery) (*dto.CreditScoreTrend, error) {
+	to := query.To
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	// to is an inclusive date
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+
+	from := query.From
+	if from.IsZero() {
+		from = to.AddDate(-1, 0, 0)
+	}
+	from = periodStart(from, query.Period)
+
+	scores, err := s.repo.GetRange(ctx, userID, from, to, maxTrendScores+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(scores) > maxTrendScores {
+		return nil, errors.InvalidRequest("Too many scores in range; narrow it with from and to").WithCode("RANGE_TOO_LARGE")
+	}
+
+	return &dto.CreditScoreTrend{
+		UserID:           userID,
+		Period:           query.Period,
+		From:             from,
+		To:               to,
+		Buckets:          bucketScores(scores, query.Period),
+		GradeTransitions: gradeTransitions(scores),
+		FactorChanges:    factorChanges(scores),
+	}, nil
+}
+
+// bucketScores groups scores (oldest first) into consecutive periods
+func bucketScores(scores []*model.CreditScore, period string) []dto.TrendBucket {
+	buckets := []dto.TrendBucket{}
+	var sum int
+
+	for _, score := range scores {
+		start := periodStart(score.CalculatedAt, period)
+
+		if len(buckets) == 0 || !buckets[len(buckets)-1].PeriodStart.Equal(start) {
+			if len(buckets) > 0 {
+				closeBucket(&buckets[len(buckets)-1], sum)
+			}
+			buckets = append(buckets, dto.TrendBucket{
+				Label:       periodLabel(start, period),
+				PeriodStart: start,
+				PeriodEnd:   periodEnd(start, period),
+				Min:         score.Score,
+				Max:         score.Score,
+			})
+			sum = 0
+		}
+
+		b := &buckets[len(buckets)-1]
+		b.Count++
+		b.Min = min(b.Min, score.Score)
+		b.Max = max(b.Max, score.Score)
+		b.Latest = score.Score
+		sum += score.Score
+	}
+	if len(buckets) > 0 {
+		closeBucket(&buckets[len(buckets)-1], sum)
+	}
+
+	// Only consecutive periods are compared; after a period with no scores
+	// there's nothing to compare against
+	for i := 1; i < len(buckets); i++ {
+		if !buckets[i-1].PeriodEnd.Equal(buckets[i].PeriodStart) {
+			continue
+		}
+		change := round2(buckets[i].Average - buckets[i-1].Average)
+		buckets[i].Change = &change
+	}
+
+	return buckets
+}
+
+func closeBucket(b *dto.TrendBucket, sum int) {
+	b.Average = round2(float64(sum) / float64(b.Count))
+}
+
+func gradeTransitions(scores []*model.CreditScore) []dto.GradeTransition {
+	transitions := []dto.GradeTransition{}
+	for i := 1; i < len(scores); i++ {
+		if scores[i].Grade != scores[i-1].Grade {
+			transitions = append(transitions, dto.GradeTransition{
+				ScoreID:      scores[i].ID,
+				From:         scores[i-1].Grade,
+				To:           scores[i].Grade,
+				CalculatedAt: scores[i].CalculatedAt,
+			})
+		}
+	}
+	return transitions
+}
+
+func factorChanges(scores []*model.CreditScore) []dto.FactorChange {
+	changes := []dto.FactorChange{}
+	for i := 1; i < len(scores); i++ {
+		added := difference(scores[i].Factors, scores[i-1].Factors)
+		removed := difference(scores[i-1].Factors, scores[i].Factors)
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+		changes = append(changes, dto.FactorChange{
+			ScoreID:      scores[i].ID,
+			CalculatedAt: scores[i].CalculatedAt,
+			Added:        added,
+			Removed:      removed,
+		})
+	}
+	return changes
+}
+
+// difference returns the elements of a that are not in b
+func difference(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, f := range b {
+		inB[f] = true
+	}
+
+	diff := []string{}
+	for _, f := range a {
+		if !inB[f] {
+			diff = append(diff, f)
+		}
+	}
+	return diff
+}
+
+func periodStart(t time.Time, period string) time.Time {
+	t = t.UTC()
+	month := t.Month()
+	if period == "quarterly" {
+		month = time.Month((int(month)-1)/3*3 + 1)
+	}
+	return time.Date(t.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func periodEnd(start time.Time, period string) time.Time {
+	if period == "quarterly" {
+		return start.AddDate(0, 3, 0)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+func periodLabel(start time.Time, period string) string {
+	if period == "quarterly" {
+		return fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())-1)/3+1)
+	}
+	return start.Format("2006-01")
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
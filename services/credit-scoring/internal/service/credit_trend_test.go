@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"credit-scoring/internal/model"
+)
+
+func TestBucketScoresChange(t *testing.T) {
+	score := func(month time.Month, value int) *model.CreditScore {
+		return &model.CreditScore{Score: value, CalculatedAt: time.Date(2024, month, 10, 0, 0, 0, 0, time.UTC)}
+	}
+
+	tests := []struct {
+		name   string
+		period string
+		scores []*model.CreditScore
+		want   []*float64
+	}{
+		{
+			name:   "consecutive months",
+			period: "monthly",
+			scores: []*model.CreditScore{score(1, 600), score(1, 620), score(2, 640)},
+			want:   []*float64{nil, ptr(30.0)},
+		},
+		{
+			name:   "month without scores",
+			period: "monthly",
+			scores: []*model.CreditScore{score(1, 600), score(3, 640), score(4, 650)},
+			want:   []*float64{nil, nil, ptr(10.0)},
+		},
+		{
+			name:   "consecutive quarters",
+			period: "quarterly",
+			scores: []*model.CreditScore{score(2, 600), score(4, 650)},
+			want:   []*float64{nil, ptr(50.0)},
+		},
+		{
+			name:   "quarter without scores",
+			period: "quarterly",
+			scores: []*model.CreditScore{score(2, 600), score(8, 650)},
+			want:   []*float64{nil, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := bucketScores(tt.scores, tt.period)
+			if len(buckets) != len(tt.want) {
+				t.Fatalf("got %d buckets, want %d", len(buckets), len(tt.want))
+			}
+			for i, b := range buckets {
+				switch {
+				case tt.want[i] == nil && b.Change != nil:
+					t.Errorf("bucket %s: change = %v, want nil", b.Label, *b.Change)
+				case tt.want[i] != nil && (b.Change == nil || *b.Change != *tt.want[i]):
+					t.Errorf("bucket %s: change = %v, want %v", b.Label, b.Change, *tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/dto"
+	"credit-scoring/pkg/bureau"
+)
+
+// reportHistoryLimit is how many past scores the report chart covers
+const reportHistoryLimit = 24
+
+// ReportService gathers the data shown in downloadable credit reports.
+type ReportService struct {
+	credit *CreditScoringService
+	bureau bureau.Client
+	logger *zap.Logger
+}
+
+func NewReportService(credit *CreditScoringService, bureau bureau.Client, logger *zap.Logger) *ReportService {
+	return &ReportService{
+		credit: credit,
+		bureau: bureau,
+		logger: logger,
+	}
+}
+
+// GetReport returns the user's current score, recent history and bureau
+// tradelines. A bureau failure doesn't fail the report; it is noted instead.
+func (s *ReportService) GetReport(ctx context.Context, userID string) (*dto.CreditReport, error) {
+	score, err := s.credit.GetScore(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.credit.GetHistory(ctx, userID, &dto.HistoryQuery{Limit: reportHistoryLimit})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.CreditReport{
+		UserID:      userID,
+		GeneratedAt: time.Now().UTC(),
+		Score:       score,
+		History:     make([]dto.CreditScore, len(history.History)),
+	}
+	// History is newest first; the chart reads left to right
+	for i, h := range history.History {
+		report.History[len(history.History)-1-i] = h
+	}
+
+	tradelines, err := s.bureau.Tradelines(ctx, userID)
+	switch {
+	case err == bureau.ErrNotConfigured:
+		report.TradelinesNote = "Bureau data is not available in this environment."
+	case err != nil:
+		s.logger.Warn("Failed to fetch bureau tradelines", zap.Error(err), zap.String("userId", userID))
+		report.TradelinesNote = "Bureau data could not be retrieved. Try again later."
+	case len(tradelines) == 0:
+		report.TradelinesNote = "No accounts reported by the bureau."
+	}
+
+	for _, t := range tradelines {
+		report.Tradelines = append(report.Tradelines, dto.Tradeline{
+			Creditor:      t.Creditor,
+			AccountType:   t.AccountType,
+			AccountNumber: maskAccountNumber(t.AccountNumber),
+			Status:        t.Status,
+			Balance:       t.Balance,
+			CreditLimit:   t.CreditLimit,
+			PaymentStatus: t.PaymentStatus,
+			OpenedAt:      t.OpenedAt,
+			ReportedAt:    t.ReportedAt,
+		})
+	}
+
+	return report, nil
+}
+
+// maskAccountNumber keeps only the last four characters, since reports end up
+// in customer files
+func maskAccountNumber(number string) string {
+	if len(number) <= 4 {
+		return number
+	}
+	return strings.Repeat("*", len(number)-4) + number[len(number)-4:]
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/tenant"
+	"credit-scoring/pkg/redis"
+)
+
+// ScoreUpdatesChannel is the Redis pub/sub channel new scores are published to
+// so every replica can push them to its stream subscribers.
+const ScoreUpdatesChannel = "credit_score_updates"
+
+// scoreStreamBuffer is how many updates a slow subscriber may fall behind
+// before updates to it are dropped
+const scoreStreamBuffer = 8
+
+const scoreStreamRetryDelay = 5 * time.Second
+
+// scoreUpdate is the message published to ScoreUpdatesChannel
+type scoreUpdate struct {
+	TenantID string           `json:"tenantId"`
+	Score    *dto.CreditScore `json:"score"`
+}
+
+// streamKey identifies whose updates a subscriber receives
+type streamKey struct {
+	tenantID string
+	userID   string
+}
+
+// ScoreStream fans score updates from Redis out to local subscribers. Each
+// replica holds a single Redis subscription regardless of subscriber count.
+type ScoreStream struct {
+	cache  *redis.RedisClient
+	logger *zap.Logger
+
+	mu          sync.Mutex
+	subscribers map[streamKey]map[chan *dto.CreditScore]struct{}
+	closed      bool
+}
+
+func NewScoreStream(cache *redis.RedisClient, logger *zap.Logger) *ScoreStream {
+	return &ScoreStream{
+		cache:       cache,
+		logger:      logger,
+		subscribers: make(map[streamKey]map[chan *dto.CreditScore]struct{}),
+	}
+}
+
+// Run relays updates from Redis until ctx is cancelled, then closes every
+// subscriber channel so open streams end.
+func (s *ScoreStream) Run(ctx context.Context) {
+	defer s.closeAll()
+
+	for {
+		err := s.cache.Subscribe(ctx, ScoreUpdatesChannel, func(payload []byte) {
+			var update scoreUpdate
+			if err := json.Unmarshal(payload, &update); err != nil || update.Score == nil {
+				s.logger.Warn("Ignoring malformed score update", zap.Error(err))
+				return
+			}
+			s.broadcast(update)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.logger.Warn("Score update subscription failed, retrying", zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(scoreStreamRetryDelay):
+		}
+	}
+}
+
+// Subscribe returns a channel of score updates for userID of tenantID. Call
+// the returned function to unsubscribe. The channel is closed when the
+// stream shuts down.
+func (s *ScoreStream) Subscribe(tenantID, userID string) (<-chan *dto.CreditScore, func()) {
+	ch := make(chan *dto.CreditScore, scoreStreamBuffer)
+	key := streamKey{tenantID: tenantID, userID: userID}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if s.subscribers[key] == nil {
+		s.subscribers[key] = make(map[chan *dto.CreditScore]struct{})
+	}
+	s.subscribers[key][ch] = struct{}{}
+	s.mu.Unlock()
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers[key], ch)
+		if len(s.subscribers[key]) == 0 {
+			delete(s.subscribers, key)
+		}
+	}
+}
+
+func (s *ScoreStream) broadcast(update scoreUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.subscribers[streamKey{tenantID: update.TenantID, userID: update.Score.UserID}] {
+		select {
+		case ch <- update.Score:
+		default:
+			s.logger.Warn("Dropping score update for slow subscriber", zap.String("userId", update.Score.UserID))
+		}
+	}
+}
+
+func (s *ScoreStream) closeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for key, chans := range s.subscribers {
+		for ch := range chans {
+			close(ch)
+		}
+		delete(s.subscribers, key)
+	}
+}
+
+// publishScoreUpdate notifies stream subscribers on every replica
+func (s *CreditScoringService) publishScoreUpdate(ctx context.Context, score *dto.CreditScore) {
+	tenantID, _ := tenant.FromContext(ctx)
+	if err := s.cache.Publish(ctx, ScoreUpdatesChannel, scoreUpdate{TenantID: tenantID, Score: score}); err != nil {
+		s.logger.Warn("Failed to publish score update", zap.Error(err), zap.String("userId", score.UserID))
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/model"
+	"credit-scoring/internal/repository"
+	"credit-scoring/pkg/errors"
+	"credit-scoring/pkg/redis"
+)
+
+// tenantSettingsTTL is how long a tenant's scoring model and quota are
+// cached, and so how long a change takes to reach every replica
+const tenantSettingsTTL = time.Minute
+
+// defaultScoringModel is the built-in model, used by tenants without one of
+// their own
+var defaultScoringModel = model.ScoringModel{
+	Version: scoreModelVersion,
+	Weights: model.ScoringWeights{
+		Income:      0.30,
+		Employment:  0.25,
+		AccountAge:  0.20,
+		LoanHistory: 0.25,
+	},
+	ValidityDays: 30,
+}
+
+func tenantSettingsCacheKey(tenantID string) string {
+	return fmt.Sprintf("tenant_settings:%s", tenantID)
+}
+
+// tenantSettings are the per-tenant settings read on the request path, cached
+// together.
+type tenantSettings struct {
+	ScoringModel      model.ScoringModel `json:"scoringModel"`
+	MonthlyScoreQuota int                `json:"monthlyScoreQuota"`
+}
+
+// TenantService manages tenants, the scoring model each one uses and their
+// monthly scoring quotas.
+type TenantService struct {
+	repo   *repository.TenantRepository
+	cache  *redis.RedisClient
+	logger *zap.Logger
+}
+
+func NewTenantService(repo *repository.TenantRepository, cache *redis.RedisClient, logger *zap.Logger) *TenantService {
+	return &TenantService{
+		repo:   repo,
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+// ScoringModel returns the model tenantID's scores are calculated with: its
+// own if it has one, otherwise the built-in model.
+func (s *TenantService) ScoringModel(ctx context.Context, tenantID string) (*model.ScoringModel, error) {
+	settings, err := s.settings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &settings.ScoringModel, nil
+}
+
+// MonthlyScoreQuota returns how many billable scoring requests tenantID may
+// make per calendar month, or zero if it has no limit.
+func (s *TenantService) MonthlyScoreQuota(ctx context.Context, tenantID string) (int, error) {
+	settings, err := s.settings(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	return settings.MonthlyScoreQuota, nil
+}
+
+// settings returns tenantID's settings from the cache, or loads and caches
+// them. Tenants without a row get the built-in model and no quota.
+func (s *TenantService) settings(ctx context.Context, tenantID string) (*tenantSettings, error) {
+	cacheKey := tenantSettingsCacheKey(tenantID)
+	var cached tenantSettings
+	if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+		return &cached, nil
+	}
+
+	settings := tenantSettings{ScoringModel: defaultScoringModel}
+	t, err := s.repo.GetTenant(ctx, tenantID)
+	switch {
+	case err == repository.ErrNotFound:
+	case err != nil:
+		return nil, fmt.Errorf("failed to load tenant: %w", err)
+	default:
+		if t.ScoringModel != nil {
+			settings.ScoringModel = *t.ScoringModel
+		}
+		settings.MonthlyScoreQuota = t.MonthlyScoreQuota
+	}
+
+	if err := s.cache.Set(ctx, cacheKey, settings, tenantSettingsTTL); err != nil {
+		s.logger.Warn("Failed to cache tenant settings", zap.Error(err), zap.String("tenantId", tenantID))
+	}
+
+	return &settings, nil
+}
+
+// CreateTenant adds a tenant that uses the built-in scoring model.
+func (s *TenantService) CreateTenant(ctx context.Context, tenantID, name string) (*model.Tenant, error) {
+	if tenantID == "" || len(tenantID) > 255 {
+		return nil, errors.InvalidRequest("Tenant ID must be 1 to 255 characters")
+	}
+
+	t := &model.Tenant{ID: tenantID, Name: name}
+	if err := s.repo.CreateTenant(ctx, t); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Tenant created", zap.String("tenantId", tenantID))
+	return t, nil
+}
+
+// SetScoringModel gives the tenant its own scoring model, or restores the
+// built-in one if m is nil. Replicas pick the change up within
+// tenantSettingsTTL.
+func (s *TenantService) SetScoringModel(ctx context.Context, tenantID string, m *model.ScoringModel) error {
+	if m != nil {
+		if err := ValidateScoringModel(m); err != nil {
+			return err
+		}
+	}
+
+	err := s.repo.SetScoringModel(ctx, tenantID, m)
+	if err == repository.ErrNotFound {
+		return errors.NotFound("Tenant not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Tenant scoring model updated", zap.String("tenantId", tenantID), zap.Bool("builtIn", m == nil))
+	return nil
+}
+
+// SetMonthlyScoreQuota limits the billable scoring requests the tenant may
+// make per calendar month; zero removes the limit. Replicas pick the change up
+// within tenantSettingsTTL.
+func (s *TenantService) SetMonthlyScoreQuota(ctx context.Context, tenantID string, quota int) error {
+	if quota < 0 {
+		return errors.InvalidRequest("Monthly score quota must not be negative")
+	}
+
+	err := s.repo.SetMonthlyScoreQuota(ctx, tenantID, quota)
+	if err == repository.ErrNotFound {
+		return errors.NotFound("Tenant not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Tenant monthly score quota updated", zap.String("tenantId", tenantID), zap.Int("quota", quota))
+	return nil
+}
+
+// ValidateScoringModel checks that m names a version, that its weights are
+// non-negative and add up to 1, and that scores stay valid for 1 to 3650
+// days.
+func ValidateScoringModel(m *model.ScoringModel) error {
+	var fields []errors.FieldError
+
+	if m.Version == "" || len(m.Version) > 50 {
+		fields = append(fields, errors.FieldError{Field: "version", Message: "must be 1 to 50 characters"})
+	}
+
+	w := m.Weights
+	if w.Income < 0 || w.Employment < 0 || w.AccountAge < 0 || w.LoanHistory < 0 {
+		fields = append(fields, errors.FieldError{Field: "weights", Message: "must not be negative"})
+	} else if math.Abs(w.Income+w.Employment+w.AccountAge+w.LoanHistory-1) > 1e-9 {
+		fields = append(fields, errors.FieldError{Field: "weights", Message: "must add up to 1"})
+	}
+
+	if m.ValidityDays < 1 || m.ValidityDays > 3650 {
+		fields = append(fields, errors.FieldError{Field: "validityDays", Message: "must be between 1 and 3650"})
+	}
+
+	if len(fields) > 0 {
+		return errors.Validation("Invalid scoring model", fields...)
+	}
+
+	return nil
+}
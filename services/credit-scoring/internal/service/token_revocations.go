@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"credit-scoring/pkg/redis"
+)
+
+// TokenRevocations is the list of revoked access tokens, kept in Redis so
+// every replica sees a logout at once. Entries expire when the tokens they
+// revoke would have.
+type TokenRevocations struct {
+	redis *redis.RedisClient
+	// sessionTTL is how long a revoked session's access tokens can still be
+	// valid, i.e. the access token lifetime
+	sessionTTL time.Duration
+}
+
+func NewTokenRevocations(redis *redis.RedisClient, accessTokenTTL time.Duration) *TokenRevocations {
+	return &TokenRevocations{
+		redis:      redis,
+		sessionTTL: accessTokenTTL,
+	}
+}
+
+func revokedTokenKey(jti string) string {
+	return fmt.Sprintf("auth:revoked:jti:%s", jti)
+}
+
+func revokedSessionKey(sid string) string {
+	return fmt.Sprintf("auth:revoked:sid:%s", sid)
+}
+
+// RevokeToken revokes one access token until it expires.
+func (r *TokenRevocations) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return r.redis.Set(ctx, revokedTokenKey(jti), true, ttl)
+}
+
+// RevokeSession revokes every access token issued in a login session.
+func (r *TokenRevocations) RevokeSession(ctx context.Context, sid string) error {
+	return r.redis.Set(ctx, revokedSessionKey(sid), true, r.sessionTTL)
+}
+
+// IsRevoked reports whether the token with jti, or its session sid, has been
+// revoked. Either may be empty.
+func (r *TokenRevocations) IsRevoked(ctx context.Context, jti, sid string) (bool, error) {
+	var keys []string
+	if jti != "" {
+		keys = append(keys, revokedTokenKey(jti))
+	}
+	if sid != "" {
+		keys = append(keys, revokedSessionKey(sid))
+	}
+	if len(keys) == 0 {
+		return false, nil
+	}
+
+	n, err := r.redis.Exists(ctx, keys...)
+	return n > 0, err
+}
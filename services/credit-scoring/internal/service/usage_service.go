@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/model"
+	"credit-scoring/internal/repository"
+	"credit-scoring/internal/tenant"
+	"credit-scoring/pkg/redis"
+)
+
+const (
+	// usageRedisTimeout bounds how long recording a request may take, so a
+	// slow Redis doesn't hold up responses
+	usageRedisTimeout = time.Second
+	// Counters outlive their period long enough to be rolled up after it
+	// ends, even if the rollup job is down for a while
+	usageDayTTL   = 72 * time.Hour
+	usageMonthTTL = 62 * 24 * time.Hour
+	// usageRollupLockKey lets one replica roll up per interval
+	usageRollupLockKey = "usage_rollup_lock"
+)
+
+// Usage counters in Redis:
+//
+//	usage:day:<tenant>:<YYYY-MM-DD>   hash of requests per field
+//	usage:month:<tenant>:<YYYY-MM>    hash of requests per field
+//	usage:tenants:<YYYY-MM-DD>        set of tenants with requests that day
+//	usage:scores:<tenant>:<YYYY-MM>   billable requests, for the quota
+//
+// Fields are "<billable>|<endpoint>|<caller>", with billable 0 or 1.
+
+func usageDayKey(tenantID string, day time.Time) string {
+	return fmt.Sprintf("usage:day:%s:%s", tenantID, day.Format("2006-01-02"))
+}
+
+func usageMonthKey(tenantID string, day time.Time) string {
+	return fmt.Sprintf("usage:month:%s:%s", tenantID, day.Format("2006-01"))
+}
+
+func usageTenantsKey(day time.Time) string {
+	return fmt.Sprintf("usage:tenants:%s", day.Format("2006-01-02"))
+}
+
+func usageScoresKey(tenantID string, day time.Time) string {
+	return fmt.Sprintf("usage:scores:%s:%s", tenantID, day.Format("2006-01"))
+}
+
+// recordUsageScript counts a request. KEYS are the day, month, tenants and
+// scores keys; ARGV[1] is the field, ARGV[2] the tenant, ARGV[3] 1 for a
+// billable request, and ARGV[4] and ARGV[5] the day and month TTLs in
+// seconds. It returns the month's billable requests so far.
+var recordUsageScript = redis.NewScript(`
+redis.call('HINCRBY', KEYS[1], ARGV[1], 1)
+redis.call('EXPIRE', KEYS[1], ARGV[4])
+redis.call('HINCRBY', KEYS[2], ARGV[1], 1)
+redis.call('EXPIRE', KEYS[2], ARGV[5])
+redis.call('SADD', KEYS[3], ARGV[2])
+redis.call('EXPIRE', KEYS[3], ARGV[4])
+
+local scores = 0
+if ARGV[3] == '1' then
+	scores = redis.call('INCR', KEYS[4])
+	redis.call('EXPIRE', KEYS[4], ARGV[5])
+end
+return {scores}
+`)
+
+// UsageService meters requests per tenant, caller and endpoint for billing,
+// and enforces tenants' monthly scoring quotas. Requests are counted in Redis
+// and rolled up to Postgres by Run, which usage reports are read from.
+type UsageService struct {
+	repo     *repository.UsageRepository
+	tenants  *TenantService
+	cache    *redis.RedisClient
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+func NewUsageService(
+	repo *repository.UsageRepository,
+	tenants *TenantService,
+	cache *redis.RedisClient,
+	interval time.Duration,
+	logger *zap.Logger,
+) *UsageService {
+	return &UsageService{
+		repo:     repo,
+		tenants:  tenants,
+		cache:    cache,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Record counts a successful request by callerID to endpoint. Billable
+// requests also count towards the tenant's monthly scoring quota. Failures
+// are logged so metering never fails the request itself.
+func (s *UsageService) Record(ctx context.Context, tenantID, callerID, endpoint string, billable bool) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), usageRedisTimeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	flag := "0"
+	if billable {
+		flag = "1"
+	}
+
+	_, err := s.cache.RunScript(ctx, recordUsageScript,
+		[]string{
+			usageDayKey(tenantID, now),
+			usageMonthKey(tenantID, now),
+			usageTenantsKey(now),
+			usageScoresKey(tenantID, now),
+		},
+		flag+"|"+endpoint+"|"+callerID,
+		tenantID,
+		flag,
+		int64(usageDayTTL/time.Second),
+		int64(usageMonthTTL/time.Second),
+	)
+	if err != nil {
+		s.logger.Error("Failed to record usage", zap.Error(err),
+			zap.String("tenantId", tenantID), zap.String("callerId", callerID), zap.String("endpoint", endpoint))
+	}
+}
+
+// ScoreQuota returns the tenant's monthly scoring quota and how much of it is
+// used. Requests in flight when the quota runs out may still complete, so it
+// can be exceeded by a few requests.
+func (s *UsageService) ScoreQuota(ctx context.Context, tenantID string) (model.UsageQuota, error) {
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	quota := model.UsageQuota{Month: month, ResetsAt: month.AddDate(0, 1, 0)}
+
+	limit, err := s.tenants.MonthlyScoreQuota(ctx, tenantID)
+	if err != nil {
+		return quota, err
+	}
+	quota.Limit = int64(limit)
+	quota.Used, err = s.monthlyScores(ctx, tenantID, month)
+
+	return quota, err
+}
+
+// monthlyScores returns the tenant's billable requests this month from Redis,
+// or as of the last rollup if Redis doesn't have them.
+func (s *UsageService) monthlyScores(ctx context.Context, tenantID string, month time.Time) (int64, error) {
+	var used int64
+	if err := s.cache.Get(ctx, usageScoresKey(tenantID, month), &used); err == nil {
+		return used, nil
+	}
+
+	used, err := s.repo.MonthlyBillable(tenant.NewContext(ctx, tenantID), month)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load monthly usage: %w", err)
+	}
+	return used, nil
+}
+
+// Report returns the tenant's usage in the range selected by query, which
+// must have been validated.
+func (s *UsageService) Report(ctx context.Context, tenantID string, query *dto.UsageQuery) (*dto.UsageReport, error) {
+	quota, err := s.ScoreQuota(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := query.From, query.To
+	if from.IsZero() {
+		from = quota.Month
+		if !to.IsZero() && to.Before(from) {
+			from = time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+		}
+	}
+	if to.IsZero() {
+		to = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+
+	granularity := repository.UsageGranularity(query.Granularity)
+	periodFrom, layout := from, "2006-01-02"
+	if granularity == repository.UsageMonthly {
+		periodFrom, layout = time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC), "2006-01"
+	}
+
+	records, err := s.repo.List(tenant.NewContext(ctx, tenantID), granularity, periodFrom, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load usage: %w", err)
+	}
+
+	report := &dto.UsageReport{
+		TenantID:    tenantID,
+		From:        from.Format("2006-01-02"),
+		To:          to.Format("2006-01-02"),
+		Granularity: query.Granularity,
+		Quota:       toUsageQuota(quota),
+		Usage:       make([]dto.UsageEntry, 0, len(records)),
+	}
+	for _, rec := range records {
+		report.Usage = append(report.Usage, dto.UsageEntry{
+			Period:   rec.Period.Format(layout),
+			CallerID: rec.CallerID,
+			Endpoint: rec.Endpoint,
+			Billable: rec.Billable,
+			Requests: rec.RequestCount,
+		})
+		report.Totals.Requests += rec.RequestCount
+		if rec.Billable {
+			report.Totals.Billable += rec.RequestCount
+		}
+	}
+
+	return report, nil
+}
+
+func toUsageQuota(q model.UsageQuota) dto.UsageQuota {
+	quota := dto.UsageQuota{
+		Month:    q.Month.Format("2006-01"),
+		Used:     q.Used,
+		ResetsAt: q.ResetsAt,
+	}
+	if q.Limit > 0 {
+		remaining := max(q.Limit-q.Used, 0)
+		quota.Limit = &q.Limit
+		quota.Remaining = &remaining
+	}
+	return quota
+}
+
+// Run rolls the usage counters up to Postgres every interval until ctx is
+// cancelled. Only one replica rolls up per interval.
+func (s *UsageService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.rollupIfLeader(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *UsageService) rollupIfLeader(ctx context.Context) {
+	// The lock is left to expire rather than released, so the other
+	// replicas skip this interval
+	ok, err := s.cache.SetNX(ctx, usageRollupLockKey, time.Now().UTC(), s.interval)
+	if err != nil {
+		s.logger.Error("Failed to take usage rollup lock", zap.Error(err))
+		return
+	}
+	if !ok {
+		return
+	}
+
+	// Yesterday too, for the requests made after its last rollup
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		if err := s.rollup(ctx, day); err != nil {
+			s.logger.Error("Failed to roll up usage", zap.Error(err), zap.Time("day", day))
+		}
+	}
+}
+
+// rollup copies the day's counters, and those of its month, of every tenant
+// with requests that day to Postgres.
+func (s *UsageService) rollup(ctx context.Context, day time.Time) error {
+	tenantIDs, err := s.cache.SMembers(ctx, usageTenantsKey(day))
+	if err != nil {
+		return err
+	}
+
+	month := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for _, tenantID := range tenantIDs {
+		tenantCtx := tenant.NewContext(ctx, tenantID)
+		if err := s.rollupCounters(tenantCtx, usageDayKey(tenantID, day), repository.UsageDaily, day); err != nil {
+			return fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+		if err := s.rollupCounters(tenantCtx, usageMonthKey(tenantID, day), repository.UsageMonthly, month); err != nil {
+			return fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *UsageService) rollupCounters(ctx context.Context, key string, granularity repository.UsageGranularity, period time.Time) error {
+	counters, err := s.cache.HGetAll(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	records := make([]model.UsageRecord, 0, len(counters))
+	for field, value := range counters {
+		flag, rest, ok := strings.Cut(field, "|")
+		endpoint, callerID, ok2 := strings.Cut(rest, "|")
+		count, err := strconv.ParseInt(value, 10, 64)
+		if !ok || !ok2 || err != nil {
+			s.logger.Warn("Skipping malformed usage counter", zap.String("key", key), zap.String("field", field))
+			continue
+		}
+		records = append(records, model.UsageRecord{
+			CallerID:     callerID,
+			Endpoint:     endpoint,
+			Billable:     flag == "1",
+			RequestCount: count,
+		})
+	}
+
+	// A stable order keeps concurrent upserts from deadlocking
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].CallerID != records[j].CallerID {
+			return records[i].CallerID < records[j].CallerID
+		}
+		return records[i].Endpoint < records[j].Endpoint
+	})
+
+	return s.repo.Upsert(ctx, granularity, period, records)
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+
+	"credit-scoring/internal/dto"
+	"credit-scoring/internal/model"
+	"credit-scoring/internal/repository"
+	"credit-scoring/internal/tenant"
+	"credit-scoring/pkg/errors"
+	"credit-scoring/pkg/id"
+	"credit-scoring/pkg/webhook"
+)
+
+const (
+	// webhookBatchSize is how many due deliveries one poll sends
+	webhookBatchSize = 50
+	// webhookLease hides claimed deliveries from other replicas while sending
+	webhookLease = 2 * time.Minute
+	// webhookBaseBackoff doubles after every failed attempt up to webhookMaxBackoff
+	webhookBaseBackoff = time.Minute
+	webhookMaxBackoff  = 12 * time.Hour
+	// expiryScanInterval is how often expired scores are looked for
+	expiryScanInterval = time.Minute
+	// webhookDeliveryPageSize is how many deliveries are listed per subscription
+	webhookDeliveryPageSize = 50
+)
+
+// WebhookService manages webhook subscriptions and delivers score events to
+// them. Deliveries are queued in the notifications table and sent by Run.
+type WebhookService struct {
+	repo        *repository.WebhookRepository
+	creditRepo  *repository.CreditRepository
+	sender      *webhook.Sender
+	maxAttempts int
+	interval    time.Duration
+	logger      *zap.Logger
+}
+
+func NewWebhookService(
+	repo *repository.WebhookRepository,
+	creditRepo *repository.CreditRepository,
+	sender *webhook.Sender,
+	maxAttempts int,
+	interval time.Duration,
+	logger *zap.Logger,
+) *WebhookService {
+	return &WebhookService{
+		repo:        repo,
+		creditRepo:  creditRepo,
+		sender:      sender,
+		maxAttempts: maxAttempts,
+		interval:    interval,
+		logger:      logger,
+	}
+}
+
+func (s *WebhookService) CreateSubscription(ctx context.Context, ownerID string, req *dto.CreateWebhookRequest) (*dto.WebhookSubscription, error) {
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = webhook.NewSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	sub := &model.WebhookSubscription{
+		ID:         id.New(id.PrefixWebhook),
+		OwnerID:    ownerID,
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     secret,
+		Active:     true,
+	}
+	if err := webhook.CheckURL(ctx, req.URL); err != nil {
+		return nil, errors.Validation("Request validation failed", errors.FieldError{
+			Field:   "url",
+			Message: "must resolve to a public address",
+		}).Wrap(err)
+	}
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	result := toSubscriptionDTO(sub)
+	result.Secret = secret
+	return result, nil
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context, ownerID string) ([]dto.WebhookSubscription, error) {
+	subs, err := s.repo.ListSubscriptions(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.WebhookSubscription, len(subs))
+	for i, sub := range subs {
+		result[i] = *toSubscriptionDTO(sub)
+	}
+	return result, nil
+}
+
+func (s *WebhookService) GetSubscription(ctx context.Context, ownerID, subscriptionID string) (*dto.WebhookSubscription, error) {
+	sub, err := s.getSubscription(ctx, ownerID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return toSubscriptionDTO(sub), nil
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, ownerID, subscriptionID string) error {
+	err := s.repo.DeleteSubscription(ctx, ownerID, subscriptionID)
+	if err == repository.ErrNotFound {
+		return errors.NotFound("Webhook subscription not found")
+	}
+	return err
+}
+
+// ListDeliveries returns the most recent deliveries to a subscription.
+func (s *WebhookService) ListDeliveries(ctx context.Context, ownerID, subscriptionID string) ([]dto.WebhookDelivery, error) {
+	if _, err := s.getSubscription(ctx, ownerID, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, subscriptionID, webhookDeliveryPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.WebhookDelivery, len(deliveries))
+	for i, d := range deliveries {
+		result[i] = *toDeliveryDTO(d)
+	}
+	return result, nil
+}
+
+// GetDelivery returns a delivery with its payload and attempt log.
+func (s *WebhookService) GetDelivery(ctx context.Context, ownerID, subscriptionID, deliveryID string) (*dto.WebhookDelivery, error) {
+	if _, err := s.getSubscription(ctx, ownerID, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	d, err := s.repo.GetDelivery(ctx, subscriptionID, deliveryID)
+	if err == repository.ErrNotFound {
+		return nil, errors.NotFound("Webhook delivery not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.repo.ListAttempts(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := toDeliveryDTO(d)
+	result.Payload = json.RawMessage(d.Payload)
+	for _, a := range attempts {
+		attempt := dto.WebhookAttempt{
+			Attempt:     a.Attempt,
+			DurationMs:  a.DurationMs,
+			AttemptedAt: a.AttemptedAt,
+		}
+		if a.StatusCode != nil {
+			attempt.StatusCode = *a.StatusCode
+		}
+		if a.Error != nil {
+			attempt.Error = *a.Error
+		}
+		result.Attempts = append(result.Attempts, attempt)
+	}
+
+	return result, nil
+}
+
+// Redeliver queues a delivery to be sent again, whatever its status.
+func (s *WebhookService) Redeliver(ctx context.Context, ownerID, subscriptionID, deliveryID string) error {
+	if _, err := s.getSubscription(ctx, ownerID, subscriptionID); err != nil {
+		return err
+	}
+
+	err := s.repo.Redeliver(ctx, subscriptionID, deliveryID)
+	if err == repository.ErrNotFound {
+		return errors.NotFound("Webhook delivery not found")
+	}
+	return err
+}
+
+func (s *WebhookService) getSubscription(ctx context.Context, ownerID, subscriptionID string) (*model.WebhookSubscription, error) {
+	sub, err := s.repo.GetSubscription(ctx, ownerID, subscriptionID)
+	if err == repository.ErrNotFound {
+		return nil, errors.NotFound("Webhook subscription not found")
+	}
+	return sub, err
+}
+
+// Enqueue queues eventType for every subscription to it in the context's
+// tenant. Delivery happens asynchronously in Run.
+func (s *WebhookService) Enqueue(ctx context.Context, eventType string, score *dto.CreditScore) error {
+	subs, err := s.repo.ListSubscriptionsForEvent(ctx, eventType)
+	if err != nil {
+		return fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	for _, sub := range subs {
+		deliveryID := id.New(id.PrefixNotification)
+		payload, err := json.Marshal(dto.WebhookEvent{
+			ID:        deliveryID,
+			Type:      eventType,
+			CreatedAt: time.Now().UTC(),
+			Data:      score,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal webhook event: %w", err)
+		}
+
+		if err := s.repo.CreateDelivery(ctx, &model.WebhookDelivery{
+			ID:             deliveryID,
+			SubscriptionID: sub.ID,
+			UserID:         score.UserID,
+			EventType:      eventType,
+			URL:            sub.URL,
+			Payload:        string(payload),
+		}); err != nil {
+			return fmt.Errorf("failed to queue webhook delivery: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// enqueueWebhooks queues eventType for subscribers. Failures are logged so a
+// webhook outage never fails the score request itself.
+func (s *CreditScoringService) enqueueWebhooks(ctx context.Context, eventType string, score *dto.CreditScore) {
+	if err := s.webhooks.Enqueue(ctx, eventType, score); err != nil {
+		s.logger.Warn("Failed to queue webhooks", zap.Error(err), zap.String("userId", score.UserID), zap.String("eventType", eventType))
+	}
+}
+
+// Run sends due deliveries and emits expiry events until ctx is cancelled.
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	var lastExpiryScan time.Time
+	for {
+		if time.Since(lastExpiryScan) >= expiryScanInterval {
+			s.enqueueExpired(ctx)
+			lastExpiryScan = time.Now()
+		}
+		s.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *WebhookService) enqueueExpired(ctx context.Context) {
+	expired, err := s.creditRepo.ClaimExpired(ctx, webhookBatchSize)
+	if err != nil {
+		s.logger.Error("Failed to find expired credit scores", zap.Error(err))
+		return
+	}
+
+	for _, score := range expired {
+		tenantCtx := tenant.NewContext(ctx, score.TenantID)
+		if err := s.Enqueue(tenantCtx, dto.EventScoreExpired, toCreditScore(score)); err != nil {
+			s.logger.Error("Failed to queue expiry webhook", zap.Error(err), zap.String("userId", score.UserID))
+		}
+	}
+}
+
+func (s *WebhookService) deliverDue(ctx context.Context) {
+	due, err := s.repo.ClaimDueDeliveries(ctx, webhookBatchSize, webhookLease)
+	if err != nil {
+		s.logger.Error("Failed to claim webhook deliveries", zap.Error(err))
+		return
+	}
+
+	for _, d := range due {
+		s.deliver(tenant.NewContext(ctx, d.TenantID), d)
+	}
+}
+
+func (s *WebhookService) deliver(ctx context.Context, d *repository.DueWebhookDelivery) {
+	result, sendErr := s.sender.Send(ctx, d.URL, d.Secret, d.ID, d.EventType, []byte(d.Payload))
+
+	attempt := &model.WebhookDeliveryAttempt{
+		NotificationID: d.ID,
+		DurationMs:     int(result.Duration.Milliseconds()),
+	}
+	var reason string
+	switch {
+	case sendErr != nil:
+		reason = sendErr.Error()
+		attempt.Error = &reason
+	default:
+		attempt.StatusCode = &result.StatusCode
+		if !result.OK() {
+			reason = fmt.Sprintf("receiver responded with status %d", result.StatusCode)
+		}
+	}
+	if err := s.repo.RecordAttempt(ctx, attempt); err != nil {
+		s.logger.Warn("Failed to log webhook attempt", zap.Error(err), zap.String("deliveryId", d.ID))
+	}
+
+	logFields := []zap.Field{
+		zap.String("deliveryId", d.ID),
+		zap.String("subscriptionId", d.SubscriptionID),
+		zap.String("eventType", d.EventType),
+		zap.Int("attempt", attempt.Attempt),
+	}
+
+	var err error
+	switch {
+	case reason == "":
+		err = s.repo.MarkDelivered(ctx, d.ID)
+		s.logger.Info("Webhook delivered", logFields...)
+	case d.RetryCount+1 >= s.maxAttempts:
+		err = s.repo.MarkFailed(ctx, d.ID, reason)
+		s.logger.Warn("Webhook delivery failed permanently", append(logFields, zap.String("reason", reason))...)
+	default:
+		err = s.repo.MarkRetry(ctx, d.ID, reason, time.Now().Add(webhookBackoff(d.RetryCount)))
+		s.logger.Info("Webhook delivery failed, will retry", append(logFields, zap.String("reason", reason))...)
+	}
+	if err != nil {
+		s.logger.Error("Failed to update webhook delivery", append(logFields, zap.Error(err))...)
+	}
+}
+
+// webhookBackoff is the delay after the given number of earlier failures
+func webhookBackoff(failures int) time.Duration {
+	backoff := float64(webhookBaseBackoff) * math.Pow(2, float64(failures))
+	return time.Duration(math.Min(backoff, float64(webhookMaxBackoff)))
+}
+
+func toSubscriptionDTO(sub *model.WebhookSubscription) *dto.WebhookSubscription {
+	return &dto.WebhookSubscription{
+		ID:         sub.ID,
+		URL:        sub.URL,
+		EventTypes: sub.EventTypes,
+		Active:     sub.Active,
+		CreatedAt:  sub.CreatedAt,
+	}
+}
+
+func toDeliveryDTO(d *model.WebhookDelivery) *dto.WebhookDelivery {
+	result := &dto.WebhookDelivery{
+		ID:            d.ID,
+		EventType:     d.EventType,
+		URL:           d.URL,
+		Status:        d.Status,
+		RetryCount:    d.RetryCount,
+		NextAttemptAt: d.NextAttemptAt,
+		DeliveredAt:   d.DeliveredAt,
+		CreatedAt:     d.CreatedAt,
+	}
+	if d.FailureReason != nil {
+		result.FailureReason = *d.FailureReason
+	}
+	return result
+}
@@ -0,0 +1,40 @@
+// Package tenant carries the tenant a request acts for. Each lending partner
+// served by the deployment is a tenant; their data is kept apart by the
+// tenant ID stored on every row and included in cache keys and events.
+package tenant
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrMissing is returned when tenant-scoped work is attempted without a
+// tenant, so it fails instead of reading or writing across tenants.
+var ErrMissing = errors.New("no tenant in context")
+
+// Default is the tenant of callers whose credentials don't name one, and of
+// data stored before tenants were introduced.
+const Default = "default"
+
+type tenantKey struct{}
+
+// NewContext returns a context acting for tenantID.
+func NewContext(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// FromContext returns the tenant stored by NewContext. ok is false without
+// one.
+func FromContext(ctx context.Context) (tenantID string, ok bool) {
+	tenantID, ok = ctx.Value(tenantKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// Require returns the tenant stored by NewContext, or ErrMissing.
+func Require(ctx context.Context) (string, error) {
+	tenantID, ok := FromContext(ctx)
+	if !ok {
+		return "", ErrMissing
+	}
+	return tenantID, nil
+}
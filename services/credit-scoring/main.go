@@ -2,27 +2,44 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
+	"credit-scoring/internal/api"
+	"credit-scoring/internal/authz"
 	"credit-scoring/internal/config"
 	"credit-scoring/internal/handler"
 	"credit-scoring/internal/middleware"
 	"credit-scoring/internal/repository"
 	"credit-scoring/internal/service"
+	"credit-scoring/pkg/bureau"
 	"credit-scoring/pkg/database"
+	"credit-scoring/pkg/export"
+	"credit-scoring/pkg/jwks"
 	"credit-scoring/pkg/kafka"
 	"credit-scoring/pkg/logger"
+	"credit-scoring/pkg/mtls"
+	pb "credit-scoring/pkg/pb/creditscoringv1"
+	"credit-scoring/pkg/ratelimit"
 	"credit-scoring/pkg/redis"
 	"credit-scoring/pkg/tracing"
+	"credit-scoring/pkg/webhook"
 )
 
 func main() {
@@ -44,6 +61,14 @@ func main() {
 		log.Fatal("Failed to load configuration", zap.Error(err))
 	}
 
+	// Operator subcommands run instead of the server
+	if len(os.Args) > 1 {
+		if err := runCommand(cfg, log, os.Args[1:]); err != nil {
+			log.Fatal("Command failed", zap.Error(err))
+		}
+		return
+	}
+
 	// Initialize tracing
 	shutdown, err := tracing.InitTracer("credit-scoring", cfg.JaegerEndpoint)
 	if err != nil {
@@ -71,23 +96,168 @@ func main() {
 		log.Fatal("Failed to initialize Kafka producer", zap.Error(err))
 	}
 	defer kafkaProducer.Close()
+	kafkaProducer.UseDeadLetterTopic(cfg.KafkaPublishDLQTopic)
+	if err := useTopicEncodings(kafkaProducer, cfg); err != nil {
+		log.Fatal("Invalid Kafka topic encoding", zap.Error(err))
+	}
 
 	// Initialize repositories
 	creditRepo := repository.NewCreditRepository(db)
-	
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	accessRepo := repository.NewAccessRepository(db)
+	authRepo := repository.NewAuthRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	tenantRepo := repository.NewTenantRepository(db)
+	usageRepo := repository.NewUsageRepository(db)
+
 	// Initialize services
+	tenantService := service.NewTenantService(tenantRepo, redisClient, log)
+	usageService := service.NewUsageService(usageRepo, tenantService, redisClient, cfg.UsageRollupInterval, log)
+	accessService := service.NewAccessService(accessRepo, log)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, log)
+	revocations := service.NewTokenRevocations(redisClient, cfg.JWTExpiry)
+	authService, err := service.NewAuthService(authRepo, revocations, tokenConfig(cfg), log)
+	if err != nil {
+		log.Fatal("Failed to initialize auth", zap.Error(err))
+	}
+	webhookService := service.NewWebhookService(
+		webhookRepo,
+		creditRepo,
+		webhook.NewSender(cfg.WebhookTimeout),
+		cfg.WebhookMaxAttempts,
+		cfg.WebhookPollInterval,
+		log,
+	)
 	creditService := service.NewCreditScoringService(
 		creditRepo,
+		tenantService,
 		redisClient,
 		kafkaProducer,
+		webhookService,
+		log,
+	)
+	reportService := service.NewReportService(
+		creditService,
+		bureau.NewClient(cfg.CreditBureauAPIURL, cfg.CreditBureauAPIKey),
 		log,
 	)
+	exportService, err := newExportService(cfg, db, log)
+	if err != nil {
+		log.Fatal("Failed to initialize exports", zap.Error(err))
+	}
 
 	// Initialize handlers
-	creditHandler := handler.NewCreditHandler(creditService, log)
+	scoreStream := service.NewScoreStream(redisClient, log)
+	creditHandler := handler.NewCreditHandler(creditService, scoreStream, accessService, log)
+	reportHandler := handler.NewReportHandler(reportService, accessService, log)
+	exportHandler := handler.NewExportHandler(exportService, log)
+	webhookHandler := handler.NewWebhookHandler(webhookService, log)
+	accessHandler := handler.NewAccessHandler(accessService, log)
+	authHandler := handler.NewAuthHandler(authService, log)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService, log)
+	usageHandler := handler.NewUsageHandler(usageService, log)
+	creditGRPCHandler := handler.NewCreditGRPCHandler(creditService, accessService, log)
+	eventHandler, err := handler.NewEventHandler(creditService, cfg.KafkaRescoreTopics, log)
+	if err != nil {
+		log.Fatal("Invalid KAFKA_RESCORE_TOPICS", zap.Error(err))
+	}
+
+	// Start the re-scoring consumer, if enabled
+	consumerCtx, stopConsumer := context.WithCancel(context.Background())
+	defer stopConsumer()
+
+	if cfg.KafkaConsumerEnabled {
+		topics := make([]string, 0, len(cfg.KafkaRescoreTopics))
+		for topic := range cfg.KafkaRescoreTopics {
+			topics = append(topics, topic)
+		}
+		sort.Strings(topics)
+
+		consumer, err := kafka.NewConsumer(kafka.ConsumerConfig{
+			Brokers:         cfg.KafkaBrokers,
+			GroupID:         cfg.KafkaConsumerGroup,
+			Topics:          append(topics, cfg.KafkaRescoreReplayTopic),
+			DeadLetterTopic: cfg.KafkaRescoreDLQTopic,
+			MaxAttempts:     cfg.KafkaConsumerMaxAttempts,
+		}, kafkaProducer, log)
+		if err != nil {
+			log.Fatal("Failed to initialize Kafka consumer", zap.Error(err))
+		}
+		defer consumer.Close()
+
+		go func() {
+			log.Info("Starting re-scoring consumer", zap.Strings("topics", topics))
+			if err := consumer.Run(consumerCtx, eventHandler.Handle); err != nil {
+				log.Error("Re-scoring consumer stopped", zap.Error(err))
+			}
+		}()
+	}
+
+	// Relay score updates from other replicas to stream subscribers
+	streamCtx, stopStream := context.WithCancel(context.Background())
+	defer stopStream()
+
+	go scoreStream.Run(streamCtx)
+
+	// Deliver webhooks and emit score expiry events
+	webhookCtx, stopWebhooks := context.WithCancel(context.Background())
+	defer stopWebhooks()
+
+	go webhookService.Run(webhookCtx)
+
+	// Roll usage counters up to Postgres for reports and billing
+	usageCtx, stopUsage := context.WithCancel(context.Background())
+	defer stopUsage()
+
+	go usageService.Run(usageCtx)
+
+	// Remove expired idempotency keys
+	idempotencyCtx, stopIdempotency := context.WithCancel(context.Background())
+	defer stopIdempotency()
+
+	go middleware.DeleteExpiredIdempotencyKeys(idempotencyCtx, idempotencyRepo, time.Hour, log)
+
+	// Load token signing keys and keep them fresh
+	verifier, signingKeys := newTokenVerifier(cfg, revocations, log)
+	keysCtx, stopKeys := context.WithCancel(context.Background())
+	defer stopKeys()
+
+	if signingKeys != nil {
+		go signingKeys.Run(keysCtx, cfg.JWTKeyRefreshInterval)
+	}
+
+	// TLS and client certificate identities
+	tlsCerts, certIdentities, err := newTLS(cfg, log)
+	if err != nil {
+		log.Fatal("Failed to set up TLS", zap.Error(err))
+	}
+	tlsCtx, stopTLS := context.WithCancel(context.Background())
+	defer stopTLS()
+
+	if tlsCerts != nil {
+		go tlsCerts.Run(tlsCtx, cfg.TLSReloadInterval)
+	}
+
+	// Rate limits, shared by all replicas through Redis
+	rateLimits, err := middleware.ParseRateLimitPolicies(cfg.RateLimitDefault, cfg.RateLimitRoutes)
+	if err != nil {
+		log.Fatal("Invalid rate limit", zap.Error(err))
+	}
+	ipLimit, err := ratelimit.ParseLimit(cfg.RateLimitIP)
+	if err != nil {
+		log.Fatal("Invalid rate limit", zap.Error(err))
+	}
+	limiter := ratelimit.New(redisClient, log)
+	rateLimitIP := middleware.RateLimitIP(limiter, ipLimit)
+	rateLimit := middleware.RateLimit(limiter, rateLimits)
 
 	// Setup router
-	router := setupRouter(creditHandler, log, cfg)
+	idempotency := middleware.Idempotency(idempotencyRepo, redisClient, cfg.IdempotencyKeyTTL, log)
+	router := setupRouter(creditHandler, reportHandler, webhookHandler, accessHandler, exportHandler, authHandler, apiKeyHandler, usageHandler, idempotency, rateLimitIP, rateLimit, usageService, verifier, apiKeyService, certIdentities, log, cfg)
+	if err := rateLimits.CheckRoutes(router.Routes()); err != nil {
+		log.Fatal("Invalid rate limit", zap.Error(err))
+	}
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -100,18 +270,53 @@ func main() {
 
 	// Start server in goroutine
 	go func() {
-		log.Info("Starting Credit Scoring Service", zap.String("port", cfg.Port))
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Info("Starting Credit Scoring Service", zap.String("port", cfg.Port), zap.Bool("tls", tlsCerts != nil))
+
+		var err error
+		if tlsCerts != nil {
+			srv.TLSConfig = tlsCerts.ServerConfig("h2", "http/1.1")
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatal("Failed to start server", zap.Error(err))
 		}
 	}()
 
+	// Start gRPC server
+	var grpcOpts []grpc.ServerOption
+	if tlsCerts != nil {
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(tlsCerts.ServerConfig("h2"))))
+	}
+	grpcServer, healthServer := setupGRPCServer(creditGRPCHandler, verifier, certIdentities, usageService, log, cfg, grpcOpts...)
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GRPCPort))
+	if err != nil {
+		log.Fatal("Failed to listen for gRPC", zap.Error(err))
+	}
+
+	go func() {
+		log.Info("Starting gRPC server", zap.String("port", cfg.GRPCPort))
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatal("Failed to start gRPC server", zap.Error(err))
+		}
+	}()
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Info("Shutting down server...")
+	stopConsumer()
+	stopStream()
+	stopWebhooks()
+	stopUsage()
+	stopIdempotency()
+	stopKeys()
+	stopTLS()
+	healthServer.Shutdown()
+	grpcServer.GracefulStop()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -123,7 +328,41 @@ func main() {
 	log.Info("Server exited")
 }
 
-func setupRouter(creditHandler *handler.CreditHandler, log *zap.Logger, cfg *config.Config) *gin.Engine {
+// useTopicEncodings sets the producer's encoder for each topic configured with
+// one, and the events published to them so dead letters can be re-encoded.
+func useTopicEncodings(producer *kafka.Producer, cfg *config.Config) error {
+	producer.UseEventType("credit-scoring-events", func() interface{} { return &kafka.CreditScoreEvent{} })
+
+	schemaRegistry := kafka.NewSchemaRegistry(cfg.SchemaRegistryURL)
+	for topic, encoding := range cfg.KafkaTopicEncodings {
+		encoder, err := kafka.NewEncoder(encoding, schemaRegistry)
+		if err != nil {
+			return fmt.Errorf("topic %s: %w", topic, err)
+		}
+		producer.UseEncoder(topic, encoder)
+	}
+	return nil
+}
+
+func setupRouter(
+	creditHandler *handler.CreditHandler,
+	reportHandler *handler.ReportHandler,
+	webhookHandler *handler.WebhookHandler,
+	accessHandler *handler.AccessHandler,
+	exportHandler *handler.ExportHandler,
+	authHandler *handler.AuthHandler,
+	apiKeyHandler *handler.APIKeyHandler,
+	usageHandler *handler.UsageHandler,
+	idempotency gin.HandlerFunc,
+	rateLimitIP gin.HandlerFunc,
+	rateLimit gin.HandlerFunc,
+	meter middleware.UsageMeter,
+	verifier *middleware.TokenVerifier,
+	apiKeys middleware.APIKeyAuthenticator,
+	certIdentities mtls.Identities,
+	log *zap.Logger,
+	cfg *config.Config,
+) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
 
@@ -132,7 +371,8 @@ func setupRouter(creditHandler *handler.CreditHandler, log *zap.Logger, cfg *con
 	router.Use(middleware.Recovery(log))
 	router.Use(middleware.CORS())
 	router.Use(middleware.RequestID())
-	router.Use(middleware.RateLimiter(100, 100)) // 100 requests per second
+	router.Use(middleware.Errors(log))
+	router.Use(rateLimitIP)
 
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
@@ -146,18 +386,221 @@ func setupRouter(creditHandler *handler.CreditHandler, log *zap.Logger, cfg *con
 	// Metrics endpoint
 	router.GET("/metrics", middleware.PrometheusHandler())
 
+	// API spec and docs UI
+	api.Register(router)
+
+	// Token endpoints authenticate with credentials instead of a token, so
+	// their rate limits apply per IP
+	auth := router.Group("/api/v1/auth")
+	auth.Use(middleware.APIVersion(1), rateLimit)
+	{
+		auth.POST("/login", authHandler.Login)
+		auth.POST("/token", authHandler.Token)
+		auth.POST("/refresh", authHandler.Refresh)
+		auth.POST("/logout", middleware.Auth(verifier, nil, nil), authHandler.Logout)
+	}
+
+	// Scoring requests are billable and limited by the tenant's monthly quota
+	scoreQuota := middleware.ScoreQuota(meter)
+
 	// API routes
 	v1 := router.Group("/api/v1")
-	v1.Use(middleware.Auth(cfg.JWTSecret))
+	v1.Use(middleware.APIVersion(1), middleware.Auth(verifier, apiKeys, certIdentities), rateLimit, middleware.Meter(meter))
 	{
+		// Endpoints superseded by /api/v2 announce their sunset
+		deprecated := middleware.Deprecated(cfg.APIV1Deprecation, cfg.APIV1Sunset, "/api/v1", "/api/v2")
+
 		credit := v1.Group("/credit")
 		{
-			credit.POST("/score", creditHandler.CalculateScore)
-			credit.GET("/score/:userId", creditHandler.GetScore)
-			credit.GET("/history/:userId", creditHandler.GetHistory)
-			credit.POST("/refresh/:userId", creditHandler.RefreshScore)
+			credit.POST("/score", deprecated, middleware.Authorize(authz.ScoreCalculate), scoreQuota, idempotency, creditHandler.CalculateScore)
+			credit.GET("/score/:userId", deprecated, middleware.Authorize(authz.ScoreRead), creditHandler.GetScore)
+			credit.GET("/history/:userId", deprecated, middleware.Authorize(authz.HistoryRead), creditHandler.GetHistory)
+			credit.GET("/trend/:userId", middleware.Authorize(authz.HistoryRead), creditHandler.GetTrend)
+			credit.GET("/stream/:userId", middleware.Authorize(authz.ScoreRead), creditHandler.StreamScore)
+			credit.GET("/report/:userId", middleware.Authorize(authz.ReportRead), reportHandler.GetReport)
+			credit.POST("/refresh/:userId", deprecated, middleware.Authorize(authz.ScoreRefresh), scoreQuota, creditHandler.RefreshScore)
+		}
+
+		webhooks := v1.Group("/webhooks")
+		webhooks.Use(middleware.Authorize(authz.WebhookManage))
+		{
+			webhooks.POST("", webhookHandler.CreateSubscription)
+			webhooks.GET("", webhookHandler.ListSubscriptions)
+			webhooks.GET("/:id", webhookHandler.GetSubscription)
+			webhooks.DELETE("/:id", webhookHandler.DeleteSubscription)
+			webhooks.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+			webhooks.GET("/:id/deliveries/:deliveryId", webhookHandler.GetDelivery)
+			webhooks.POST("/:id/deliveries/:deliveryId/redeliver", webhookHandler.Redeliver)
+		}
+
+		grants := v1.Group("/grants")
+		grants.Use(middleware.Authorize(authz.GrantManage))
+		{
+			grants.POST("", accessHandler.CreateGrant)
+			grants.GET("", accessHandler.ListGrants)
+			grants.DELETE("/:id", accessHandler.RevokeGrant)
+		}
+
+		v1.GET("/usage", middleware.Authorize(authz.UsageRead), usageHandler.GetUsage)
+
+		admin := v1.Group("/admin")
+		{
+			admin.POST("/exports", middleware.Authorize(authz.ExportCreate), exportHandler.CreateExport)
+
+			keys := admin.Group("/api-keys")
+			keys.Use(middleware.Authorize(authz.APIKeyManage))
+			{
+				keys.POST("", apiKeyHandler.CreateKey)
+				keys.GET("", apiKeyHandler.ListKeys)
+				keys.DELETE("/:id", apiKeyHandler.RevokeKey)
+			}
+		}
+	}
+
+	v2 := router.Group("/api/v2")
+	v2.Use(middleware.APIVersion(2), middleware.Auth(verifier, apiKeys, certIdentities), rateLimit, middleware.Meter(meter))
+	{
+		credit := v2.Group("/credit")
+		{
+			credit.POST("/score", middleware.Authorize(authz.ScoreCalculate), scoreQuota, idempotency, creditHandler.CalculateScoreV2)
+			credit.GET("/score/:userId", middleware.Authorize(authz.ScoreRead), creditHandler.GetScoreV2)
+			credit.GET("/history/:userId", middleware.Authorize(authz.HistoryRead), creditHandler.GetHistoryV2)
+			credit.POST("/refresh/:userId", middleware.Authorize(authz.ScoreRefresh), scoreQuota, creditHandler.RefreshScoreV2)
 		}
 	}
 
 	return router
 }
+
+func setupGRPCServer(creditHandler *handler.CreditGRPCHandler, verifier *middleware.TokenVerifier, certIdentities mtls.Identities, meter middleware.UsageMeter, log *zap.Logger, cfg *config.Config, opts ...grpc.ServerOption) (*grpc.Server, *health.Server) {
+	server := grpc.NewServer(append(opts, grpc.ChainUnaryInterceptor(
+		middleware.GRPCRequestID(),
+		middleware.GRPCLogger(log),
+		middleware.GRPCRecovery(log),
+		middleware.GRPCErrors(log),
+		middleware.GRPCAuth(verifier, certIdentities, "/grpc.health.v1.Health/"),
+		middleware.GRPCAuthorize(grpcPermissions, "/grpc.health.v1.Health/"),
+		middleware.GRPCMeter(meter, grpcBillable, "/grpc.health.v1.Health/"),
+	), grpc.ChainStreamInterceptor(
+		// Health Watch and reflection; reflection needs an authenticated caller
+		middleware.GRPCStreamRequestID(),
+		middleware.GRPCStreamLogger(log),
+		middleware.GRPCStreamRecovery(log),
+		middleware.GRPCStreamErrors(log),
+		middleware.GRPCStreamAuth(verifier, certIdentities, "/grpc.health.v1.Health/"),
+	))...)
+
+	pb.RegisterCreditScoringServiceServer(server, creditHandler)
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	healthServer.SetServingStatus(pb.CreditScoringService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	reflection.Register(server)
+
+	return server, healthServer
+}
+
+// grpcPermissions are the permissions the gRPC methods need, matching their
+// REST equivalents
+var grpcPermissions = map[string]authz.Permission{
+	pb.CreditScoringService_CalculateScore_FullMethodName: authz.ScoreCalculate,
+	pb.CreditScoringService_GetScore_FullMethodName:       authz.ScoreRead,
+	pb.CreditScoringService_GetHistory_FullMethodName:     authz.HistoryRead,
+	pb.CreditScoringService_RefreshScore_FullMethodName:   authz.ScoreRefresh,
+}
+
+// grpcBillable are the gRPC methods limited by the monthly scoring quota,
+// matching the REST routes with ScoreQuota
+var grpcBillable = map[string]bool{
+	pb.CreditScoringService_CalculateScore_FullMethodName: true,
+	pb.CreditScoringService_RefreshScore_FullMethodName:   true,
+}
+
+// newTokenVerifier builds the bearer token verifier. When tokens are signed
+// with keys from a JWKS endpoint or key file, the key set is returned so the
+// caller can keep it refreshed.
+func newTokenVerifier(cfg *config.Config, revocations middleware.RevocationList, log *zap.Logger) (*middleware.TokenVerifier, *jwks.Set) {
+	var keys *jwks.Set
+	switch {
+	case cfg.JWTJWKSURL != "":
+		keys = jwks.NewRemote(cfg.JWTJWKSURL, log)
+	case cfg.JWTPublicKeyFile != "":
+		keys = jwks.NewFile(cfg.JWTPublicKeyFile, log)
+	}
+
+	if keys != nil {
+		// Unknown key IDs trigger a refresh, so a failure here isn't fatal
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := keys.Refresh(ctx); err != nil {
+			log.Error("Failed to load signing keys", zap.Error(err))
+		}
+	}
+
+	return middleware.NewTokenVerifier(middleware.TokenVerifierConfig{
+		Secret:      cfg.JWTSecret,
+		Keys:        keys,
+		Issuer:      cfg.JWTIssuer,
+		Audience:    cfg.JWTAudience,
+		Leeway:      cfg.JWTClockSkew,
+		Revocations: revocations,
+	}), keys
+}
+
+// newTLS loads the server certificate and client CAs when TLS is
+// configured, and the roles of client certificate identities. The reloader
+// is nil for plaintext.
+func newTLS(cfg *config.Config, log *zap.Logger) (*mtls.Reloader, mtls.Identities, error) {
+	identities := mtls.Identities(cfg.MTLSIdentities)
+	for identity, role := range identities {
+		if !authz.KnownRole(role) {
+			return nil, nil, fmt.Errorf("MTLS_IDENTITIES maps %s to unknown role %q", identity, role)
+		}
+	}
+
+	if cfg.TLSCertFile == "" {
+		return nil, identities, nil
+	}
+
+	reloader, err := mtls.NewReloader(mtls.Config{
+		CertFile:          cfg.TLSCertFile,
+		KeyFile:           cfg.TLSKeyFile,
+		ClientCAFile:      cfg.TLSClientCAFile,
+		RequireClientCert: cfg.TLSRequireClientCert,
+	}, log)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reloader, identities, nil
+}
+
+// tokenConfig configures the tokens this service issues so that its own
+// verifier accepts them.
+func tokenConfig(cfg *config.Config) service.TokenConfig {
+	return service.TokenConfig{
+		Secret:     cfg.JWTSecret,
+		Issuer:     cfg.JWTIssuer,
+		Audience:   cfg.JWTAudience,
+		AccessTTL:  cfg.JWTExpiry,
+		RefreshTTL: cfg.JWTRefreshExpiry,
+	}
+}
+
+// newExportService builds the warehouse export service for the configured
+// destination. It is shared by the server and the export command.
+func newExportService(cfg *config.Config, db *sql.DB, log *zap.Logger) (*service.ExportService, error) {
+	sink, err := export.NewSink(cfg.ExportDestination, cfg.ExportDir, export.S3Config{
+		Endpoint:  cfg.ExportS3Endpoint,
+		Region:    cfg.ExportS3Region,
+		Bucket:    cfg.ExportS3Bucket,
+		AccessKey: cfg.ExportS3AccessKey,
+		SecretKey: cfg.ExportS3SecretKey,
+		UseSSL:    cfg.ExportS3UseSSL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return service.NewExportService(repository.NewExportRepository(db), sink, log), nil
+}
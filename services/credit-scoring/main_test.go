@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"credit-scoring/internal/api"
+	"credit-scoring/internal/config"
+	"credit-scoring/internal/middleware"
+)
+
+// TestRoutesMatchSpec fails when the router and the OpenAPI spec drift apart.
+// Handlers are never called, so they can be nil.
+func TestRoutesMatchSpec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{JWTSecret: "test"}
+	verifier := middleware.NewTokenVerifier(middleware.TokenVerifierConfig{Secret: cfg.JWTSecret})
+	noop := func(*gin.Context) {}
+
+	router := setupRouter(nil, nil, nil, nil, nil, nil, nil, nil, noop, noop, noop, nil, verifier, nil, nil, nil, cfg)
+	if err := api.Verify(router.Routes()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,97 @@
+package bureau
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNotConfigured is returned when no bureau URL is configured.
+var ErrNotConfigured = errors.New("credit bureau is not configured")
+
+// Tradeline is a credit account reported to the bureau.
+type Tradeline struct {
+	Creditor      string     `json:"creditor"`
+	AccountType   string     `json:"accountType"`
+	AccountNumber string     `json:"accountNumber"`
+	Status        string     `json:"status"`
+	Balance       float64    `json:"balance"`
+	CreditLimit   float64    `json:"creditLimit"`
+	PaymentStatus string     `json:"paymentStatus"`
+	OpenedAt      time.Time  `json:"openedAt"`
+	ClosedAt      *time.Time `json:"closedAt,omitempty"`
+	ReportedAt    time.Time  `json:"reportedAt"`
+}
+
+// Client fetches a consumer's bureau file.
+type Client interface {
+	Tradelines(ctx context.Context, userID string) ([]Tradeline, error)
+}
+
+// NewClient returns an HTTP client for the bureau at baseURL, or one that
+// always returns ErrNotConfigured when baseURL is empty.
+func NewClient(baseURL, apiKey string) Client {
+	if baseURL == "" {
+		return unconfigured{}
+	}
+	return NewHTTPClient(baseURL, apiKey)
+}
+
+type unconfigured struct{}
+
+func (unconfigured) Tradelines(ctx context.Context, userID string) ([]Tradeline, error) {
+	return nil, ErrNotConfigured
+}
+
+// HTTPClient talks to the bureau's REST API.
+type HTTPClient struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+func NewHTTPClient(baseURL, apiKey string) *HTTPClient {
+	return &HTTPClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *HTTPClient) Tradelines(ctx context.Context, userID string) ([]Tradeline, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s/v1/consumers/%s/tradelines", c.baseURL, url.PathEscape(userID)), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-API-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach credit bureau: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		// No file at the bureau means no reported accounts
+		return nil, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("credit bureau returned %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Tradelines []Tradeline `json:"tradelines"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode bureau response: %w", err)
+	}
+
+	return result.Tradelines, nil
+}
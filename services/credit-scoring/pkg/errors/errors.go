@@ -1,17 +1,203 @@
 package errors
 
-type APIError struct {
-	Code    string `json:"code"`
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"strings"
+)
+
+// Kind classifies an error and determines its HTTP status.
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindNotAcceptable
+	KindConflict
+	KindRateLimited
+	KindUpstream
+	KindUnavailable
+)
+
+var kindStatus = map[Kind]int{
+	KindInternal:      http.StatusInternalServerError,
+	KindValidation:    http.StatusBadRequest,
+	KindUnauthorized:  http.StatusUnauthorized,
+	KindForbidden:     http.StatusForbidden,
+	KindNotFound:      http.StatusNotFound,
+	KindNotAcceptable: http.StatusNotAcceptable,
+	KindConflict:      http.StatusConflict,
+	KindRateLimited:   http.StatusTooManyRequests,
+	KindUpstream:      http.StatusBadGateway,
+	KindUnavailable:   http.StatusServiceUnavailable,
+}
+
+var kindCode = map[Kind]string{
+	KindInternal:      "INTERNAL_ERROR",
+	KindValidation:    "VALIDATION_ERROR",
+	KindUnauthorized:  "UNAUTHORIZED",
+	KindForbidden:     "FORBIDDEN",
+	KindNotFound:      "NOT_FOUND",
+	KindNotAcceptable: "NOT_ACCEPTABLE",
+	KindConflict:      "CONFLICT",
+	KindRateLimited:   "RATE_LIMIT_EXCEEDED",
+	KindUpstream:      "UPSTREAM_ERROR",
+	KindUnavailable:   "SERVICE_UNAVAILABLE",
+}
+
+// Error is a domain error. Message is safe to show to clients; Err is the
+// underlying cause and is only logged.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// FieldError describes why a single request field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
 }
 
-func NewAPIError(code, message string) *APIError {
-	return &APIError{
-		Code:    code,
+func New(kind Kind, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    kindCode[kind],
 		Message: message,
 	}
 }
 
-func (e *APIError) Error() string {
+func NotFound(message string) *Error {
+	return New(KindNotFound, message)
+}
+
+// NotAcceptable reports that the requested representation, such as an API
+// version, can't be served.
+func NotAcceptable(message string) *Error {
+	return New(KindNotAcceptable, message)
+}
+
+func Validation(message string, fields ...FieldError) *Error {
+	e := New(KindValidation, message)
+	e.Fields = fields
+	return e
+}
+
+// InvalidRequest reports a request that could not be parsed at all.
+func InvalidRequest(message string) *Error {
+	return New(KindValidation, message).WithCode("INVALID_REQUEST")
+}
+
+func Conflict(message string) *Error {
+	return New(KindConflict, message)
+}
+
+func Unauthorized(message string) *Error {
+	return New(KindUnauthorized, message)
+}
+
+func Forbidden(message string) *Error {
+	return New(KindForbidden, message)
+}
+
+func RateLimited(message string) *Error {
+	return New(KindRateLimited, message)
+}
+
+// Upstream reports a failure of a dependency such as the credit bureau.
+func Upstream(message string, err error) *Error {
+	return New(KindUpstream, message).Wrap(err)
+}
+
+// Unavailable reports that the service cannot currently handle the request,
+// e.g. because the database or cache is down.
+func Unavailable(message string, err error) *Error {
+	return New(KindUnavailable, message).Wrap(err)
+}
+
+func Internal(message string, err error) *Error {
+	return New(KindInternal, message).Wrap(err)
+}
+
+// WithCode overrides the default machine-readable code for the error's kind.
+func (e *Error) WithCode(code string) *Error {
+	e.Code = code
+	return e
+}
+
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
 	return e.Message
 }
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Status() int {
+	return kindStatus[e.Kind]
+}
+
+// Problem is an RFC 7807 problem details body.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"requestId,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// ProblemContentType is the media type for Problem bodies.
+const ProblemContentType = "application/problem+json"
+
+// ToProblem maps err to a problem. Errors that are not domain errors are
+// reported as internal errors without exposing their message.
+func ToProblem(err error) *Problem {
+	e, ok := As(err)
+	if !ok {
+		if stderrors.Is(err, context.DeadlineExceeded) {
+			e = Unavailable("The request timed out", err)
+		} else {
+			e = Internal("An unexpected error occurred", err)
+		}
+	}
+
+	status := e.Status()
+	return &Problem{
+		Type:   "/problems/" + strings.ToLower(strings.ReplaceAll(e.Code, "_", "-")),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: e.Message,
+		Code:   e.Code,
+		Errors: e.Fields,
+	}
+}
+
+// As returns the domain error in err's chain, if any.
+func As(err error) (*Error, bool) {
+	var e *Error
+	ok := stderrors.As(err, &e)
+	return e, ok
+}
+
+// IsKind reports whether err is a domain error of the given kind.
+func IsKind(err error, kind Kind) bool {
+	e, ok := As(err)
+	return ok && e.Kind == kind
+}
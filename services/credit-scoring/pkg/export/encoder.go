@@ -0,0 +1,159 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// Formats an export can be written in.
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+// ContentTypes maps each format to the content type stored with the file.
+var ContentTypes = map[string]string{
+	FormatCSV:     "text/csv",
+	FormatParquet: "application/vnd.apache.parquet",
+}
+
+// Score is one exported credit_scores row.
+type Score struct {
+	ID             string
+	TenantID       string
+	UserID         string
+	Score          int
+	Grade          string
+	Factors        []string
+	Recommendation string
+	CalculatedAt   time.Time
+	ExpiresAt      time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Encoder writes scores in an export format.
+type Encoder interface {
+	Encode(s *Score) error
+	// Close flushes buffered rows and writes any trailer. It doesn't close
+	// the underlying writer.
+	Close() error
+}
+
+func NewEncoder(format string, w io.Writer) (Encoder, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVEncoder(w)
+	case FormatParquet:
+		return newParquetEncoder(w)
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+var csvHeader = []string{
+	"id", "user_id", "score", "grade", "factors", "recommendation",
+	"calculated_at", "expires_at", "created_at", "updated_at", "tenant_id",
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer) (*csvEncoder, error) {
+	e := &csvEncoder{w: csv.NewWriter(w)}
+	if err := e.w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *csvEncoder) Encode(s *Score) error {
+	// Factors are a JSON array so warehouse loaders can parse them back
+	factors, err := json.Marshal(s.Factors)
+	if err != nil {
+		return err
+	}
+	if s.Factors == nil {
+		factors = []byte("[]")
+	}
+
+	return e.w.Write([]string{
+		s.ID,
+		s.UserID,
+		strconv.Itoa(s.Score),
+		s.Grade,
+		string(factors),
+		s.Recommendation,
+		csvTime(s.CalculatedAt),
+		csvTime(s.ExpiresAt),
+		csvTime(s.CreatedAt),
+		csvTime(s.UpdatedAt),
+		s.TenantID,
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func csvTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// parquetScore is the Parquet schema for Score. Timestamps are microseconds
+// since the epoch, UTC, matching PostgreSQL's precision.
+type parquetScore struct {
+	ID             string   `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	UserID         string   `parquet:"name=user_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Score          int32    `parquet:"name=score, type=INT32"`
+	Grade          string   `parquet:"name=grade, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Factors        []string `parquet:"name=factors, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Recommendation string   `parquet:"name=recommendation, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CalculatedAt   int64    `parquet:"name=calculated_at, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+	ExpiresAt      int64    `parquet:"name=expires_at, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+	CreatedAt      int64    `parquet:"name=created_at, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+	UpdatedAt      int64    `parquet:"name=updated_at, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+	TenantID       string   `parquet:"name=tenant_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+type parquetEncoder struct {
+	w *writer.ParquetWriter
+}
+
+func newParquetEncoder(w io.Writer) (*parquetEncoder, error) {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(parquetScore), 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+	return &parquetEncoder{w: pw}, nil
+}
+
+func (e *parquetEncoder) Encode(s *Score) error {
+	return e.w.Write(parquetScore{
+		ID:             s.ID,
+		UserID:         s.UserID,
+		Score:          int32(s.Score),
+		Grade:          s.Grade,
+		Factors:        s.Factors,
+		Recommendation: s.Recommendation,
+		CalculatedAt:   s.CalculatedAt.UnixMicro(),
+		ExpiresAt:      s.ExpiresAt.UnixMicro(),
+		CreatedAt:      s.CreatedAt.UnixMicro(),
+		UpdatedAt:      s.UpdatedAt.UnixMicro(),
+		TenantID:       s.TenantID,
+	})
+}
+
+func (e *parquetEncoder) Close() error {
+	return e.w.WriteStop()
+}
@@ -0,0 +1,182 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// Destinations an export can be written to.
+const (
+	DestinationLocal = "local"
+	DestinationS3    = "s3"
+)
+
+// s3PartSize bounds the memory used to upload an export of unknown size
+const s3PartSize = 16 << 20
+
+var errAborted = errors.New("export aborted")
+
+// Sink stores export files.
+type Sink interface {
+	// Create starts writing the object at key. Nothing appears at key until
+	// the returned Object is closed without error.
+	Create(ctx context.Context, key, contentType string) (Object, error)
+	// Location describes where key is stored, e.g. "s3://bucket/key".
+	Location(key string) string
+}
+
+// Object is an export file being written.
+type Object interface {
+	io.Writer
+	// Close finishes the write and makes the object visible.
+	Close() error
+	// Abort discards everything written so far.
+	Abort()
+}
+
+// S3Config locates an S3-compatible bucket such as AWS S3 or MinIO.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+}
+
+// NewSink returns the sink for destination: files under dir, or objects in
+// the S3 bucket.
+func NewSink(destination, dir string, s3 S3Config) (Sink, error) {
+	switch destination {
+	case DestinationLocal:
+		return NewLocalSink(dir), nil
+	case DestinationS3:
+		return NewS3Sink(s3)
+	default:
+		return nil, fmt.Errorf("unknown export destination %q", destination)
+	}
+}
+
+// LocalSink writes exports to a directory.
+type LocalSink struct {
+	dir string
+}
+
+func NewLocalSink(dir string) *LocalSink {
+	return &LocalSink{dir: dir}
+}
+
+func (s *LocalSink) Create(ctx context.Context, key, contentType string) (Object, error) {
+	path := s.Location(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	// Write beside the destination and rename on close so readers never see
+	// a partial file
+	f, err := os.CreateTemp(filepath.Dir(path), ".export-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export file: %w", err)
+	}
+	return &localObject{File: f, path: path}, nil
+}
+
+func (s *LocalSink) Location(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
+
+type localObject struct {
+	*os.File
+	path string
+}
+
+func (o *localObject) Close() error {
+	if err := o.File.Close(); err != nil {
+		os.Remove(o.Name())
+		return err
+	}
+	if err := os.Rename(o.Name(), o.path); err != nil {
+		os.Remove(o.Name())
+		return err
+	}
+	return nil
+}
+
+func (o *localObject) Abort() {
+	o.File.Close()
+	os.Remove(o.Name())
+}
+
+// S3Sink uploads exports to an S3-compatible bucket.
+type S3Sink struct {
+	client *minio.Client
+	bucket string
+}
+
+func NewS3Sink(cfg S3Config) (*S3Sink, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3 export requires an endpoint and bucket")
+	}
+
+	// minio-go wants host[:port]; accept a URL too
+	endpoint := strings.TrimPrefix(strings.TrimPrefix(cfg.Endpoint, "https://"), "http://")
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	return &S3Sink{client: client, bucket: cfg.Bucket}, nil
+}
+
+func (s *S3Sink) Create(ctx context.Context, key, contentType string) (Object, error) {
+	pr, pw := io.Pipe()
+	obj := &s3Object{PipeWriter: pw, done: make(chan error, 1)}
+
+	// Stream the upload as it is written; multipart parts keep memory bounded
+	go func() {
+		_, err := s.client.PutObject(ctx, s.bucket, key, pr, -1, minio.PutObjectOptions{
+			ContentType: contentType,
+			PartSize:    s3PartSize,
+		})
+		pr.CloseWithError(err)
+		obj.done <- err
+	}()
+
+	return obj, nil
+}
+
+func (s *S3Sink) Location(key string) string {
+	return "s3://" + s.bucket + "/" + key
+}
+
+type s3Object struct {
+	*io.PipeWriter
+	done chan error
+}
+
+func (o *s3Object) Close() error {
+	o.PipeWriter.Close()
+	if err := <-o.done; err != nil {
+		return fmt.Errorf("failed to upload export: %w", err)
+	}
+	return nil
+}
+
+func (o *s3Object) Abort() {
+	// The failed read makes PutObject abort the multipart upload
+	o.PipeWriter.CloseWithError(errAborted)
+	<-o.done
+}
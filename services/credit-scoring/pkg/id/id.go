@@ -0,0 +1,134 @@
+package id
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Prefixes for the entities this platform mints IDs for.
+const (
+	PrefixCreditScore    = "cs"
+	PrefixRiskAssessment = "ra"
+	PrefixFraudAlert     = "fa"
+	PrefixVerification   = "uv"
+	PrefixNotification   = "nt"
+	PrefixWebhook        = "wh"
+	PrefixAccessGrant    = "ag"
+	PrefixUser           = "us"
+	PrefixAPIClient      = "cl"
+	PrefixRefreshToken   = "rt"
+	PrefixSession        = "se"
+	PrefixAPIKey         = "ak"
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs. It preserves sort
+// order and omits easily-confused letters.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// encodedLength is the length of an encoded ULID without prefix.
+const encodedLength = 26
+
+var defaultGenerator = &Generator{}
+
+// New returns a prefixed ULID such as "cs_01HV3K8Z6T6W9Q1X4N5R7B2C0D".
+//
+// IDs are 48 bits of millisecond timestamp followed by 80 random bits, so they
+// sort by creation time and are unique across replicas without coordination.
+// Within a process, IDs minted in the same millisecond are strictly increasing.
+func New(prefix string) string {
+	return defaultGenerator.New(prefix)
+}
+
+// Generator mints monotonic IDs. The zero value is ready to use.
+type Generator struct {
+	mu      sync.Mutex
+	lastMs  uint64
+	entropy [10]byte
+}
+
+func (g *Generator) New(prefix string) string {
+	var ulid [16]byte
+
+	g.mu.Lock()
+	ms := uint64(time.Now().UnixMilli())
+	if ms > g.lastMs {
+		g.lastMs = ms
+		if _, err := rand.Read(g.entropy[:]); err != nil {
+			panic(fmt.Sprintf("id: failed to read random bytes: %v", err))
+		}
+	} else if !increment(g.entropy[:]) {
+		// The random part overflowed; borrow the next millisecond
+		g.lastMs++
+		if _, err := rand.Read(g.entropy[:]); err != nil {
+			panic(fmt.Sprintf("id: failed to read random bytes: %v", err))
+		}
+	}
+
+	ms = g.lastMs
+	copy(ulid[6:], g.entropy[:])
+	g.mu.Unlock()
+
+	ulid[0] = byte(ms >> 40)
+	ulid[1] = byte(ms >> 32)
+	ulid[2] = byte(ms >> 24)
+	ulid[3] = byte(ms >> 16)
+	ulid[4] = byte(ms >> 8)
+	ulid[5] = byte(ms)
+
+	return prefix + "_" + encode(ulid)
+}
+
+// Time returns the creation time embedded in an ID minted by New.
+func Time(id string) (time.Time, error) {
+	_, ulid, ok := strings.Cut(id, "_")
+	if !ok || len(ulid) != encodedLength {
+		return time.Time{}, fmt.Errorf("id: malformed id %q", id)
+	}
+
+	// The first 10 characters hold the 48-bit timestamp (50 bits, top 2 zero)
+	var ms uint64
+	for i := 0; i < 10; i++ {
+		v := strings.IndexByte(crockford, ulid[i])
+		if v < 0 {
+			return time.Time{}, fmt.Errorf("id: malformed id %q", id)
+		}
+		ms = ms<<5 | uint64(v)
+	}
+
+	return time.UnixMilli(int64(ms)), nil
+}
+
+// increment adds one to b as a big-endian integer and reports false on overflow.
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encode writes the 128-bit value as 26 Crockford base32 characters.
+func encode(u [16]byte) string {
+	var out [encodedLength]byte
+
+	// 130 output bits for 128 input bits: the first character carries 3 bits
+	var acc uint64
+	bits := 2
+	j := 0
+	for _, b := range u {
+		acc = acc<<8 | uint64(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out[j] = crockford[(acc>>uint(bits))&0x1f]
+			j++
+		}
+	}
+
+	return string(out[:])
+}
@@ -0,0 +1,123 @@
+package id
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+func TestNewConcurrent(t *testing.T) {
+	const goroutines, perGoroutine = 32, 2000
+
+	var g Generator
+	before := time.Now().Truncate(time.Millisecond)
+
+	minted := make([][]string, goroutines)
+	var wg sync.WaitGroup
+	for i := range minted {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids := make([]string, perGoroutine)
+			for j := range ids {
+				ids[j] = g.New(PrefixCreditScore)
+			}
+			minted[i] = ids
+		}(i)
+	}
+	wg.Wait()
+	// Bursts may borrow milliseconds from the future
+	after := time.Now().Add(time.Second)
+
+	seen := make(map[string]bool, goroutines*perGoroutine)
+	var all []string
+	for _, ids := range minted {
+		for j, id := range ids {
+			if seen[id] {
+				t.Fatalf("duplicate id %s", id)
+			}
+			seen[id] = true
+
+			// Each goroutine's IDs were minted in order, so must sort in order
+			if j > 0 && id <= ids[j-1] {
+				t.Fatalf("id %s minted after %s sorts before it", id, ids[j-1])
+			}
+
+			created, err := Time(id)
+			if err != nil {
+				t.Fatalf("Time(%s): %v", id, err)
+			}
+			if created.Before(before) || created.After(after) {
+				t.Fatalf("id %s has time %v outside [%v, %v]", id, created, before, after)
+			}
+
+			if got := PrefixCreditScore + "_" + encode(decode(t, id)); got != id {
+				t.Fatalf("id %s round-trips to %s", id, got)
+			}
+		}
+		all = append(all, ids...)
+	}
+
+	// Sorting the IDs sorts them by creation time
+	sort.Strings(all)
+	var last time.Time
+	for _, id := range all {
+		created, _ := Time(id)
+		if created.Before(last) {
+			t.Fatalf("id %s sorts after an id created later", id)
+		}
+		last = created
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	roundTrips := func(u [16]byte) bool {
+		s := encode(u)
+		return len(s) == encodedLength && decode(t, "x_"+s) == u
+	}
+	if err := quick.Check(roundTrips, &quick.Config{MaxCount: 10000}); err != nil {
+		t.Error(err)
+	}
+
+	sortsInOrder := func(a, b [16]byte) bool {
+		return (string(a[:]) < string(b[:])) == (encode(a) < encode(b))
+	}
+	if err := quick.Check(sortsInOrder, &quick.Config{MaxCount: 10000}); err != nil {
+		t.Error(err)
+	}
+}
+
+// decode reverses encode, failing the test if id is malformed.
+func decode(t *testing.T, id string) [16]byte {
+	t.Helper()
+
+	_, s, _ := strings.Cut(id, "_")
+	if len(s) != encodedLength {
+		t.Fatalf("malformed id %q", id)
+	}
+
+	var u [16]byte
+	var acc uint64
+	bits, j := 0, 0
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(crockford, s[i])
+		if v < 0 {
+			t.Fatalf("malformed id %q", id)
+		}
+		acc = acc<<5 | uint64(v)
+		bits += 5
+		// The first character only carries 3 bits
+		if i == 0 {
+			bits -= 2
+		}
+		if bits >= 8 {
+			bits -= 8
+			u[j] = byte(acc >> uint(bits))
+			j++
+		}
+	}
+	return u
+}
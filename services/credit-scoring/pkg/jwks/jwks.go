@@ -0,0 +1,188 @@
+// Package jwks loads the public keys an identity provider signs tokens with,
+// from a JWKS endpoint or a local file, and keeps them fresh so the provider
+// can rotate keys without this service being redeployed.
+package jwks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrKeyNotFound is returned when no key has the requested ID.
+var ErrKeyNotFound = errors.New("signing key not found")
+
+const (
+	// minRefreshInterval limits refreshes triggered by unknown key IDs, so
+	// tokens with made-up kids can't be used to hammer the provider
+	minRefreshInterval = time.Minute
+	// maxSetSize caps the size of a fetched key set
+	maxSetSize = 1 << 20
+)
+
+// Set is a refreshable set of signing keys looked up by key ID.
+type Set struct {
+	source string
+	load   func(ctx context.Context) ([]byte, error)
+	logger *zap.Logger
+
+	mu          sync.RWMutex
+	keys        map[string]Key
+	lastAttempt time.Time
+
+	// refreshMu serializes refreshes
+	refreshMu sync.Mutex
+}
+
+// NewRemote returns a key set fetched from a JWKS URL.
+func NewRemote(url string, logger *zap.Logger) *Set {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	return &Set{
+		source: url,
+		logger: logger,
+		load: func(ctx context.Context) ([]byte, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Accept", "application/json")
+
+			resp, err := client.Do(req)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("JWKS endpoint returned status %d", resp.StatusCode)
+			}
+			return io.ReadAll(io.LimitReader(resp.Body, maxSetSize))
+		},
+	}
+}
+
+// NewFile returns a key set read from a local JWK Set or PEM file, such as
+// a mounted secret. Refreshing re-reads the file.
+func NewFile(path string, logger *zap.Logger) *Set {
+	return &Set{
+		source: path,
+		logger: logger,
+		load: func(context.Context) ([]byte, error) {
+			return os.ReadFile(path)
+		},
+	}
+}
+
+// Refresh reloads the keys. On failure the current keys are kept.
+func (s *Set) Refresh(ctx context.Context) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	return s.refresh(ctx)
+}
+
+func (s *Set) refresh(ctx context.Context) error {
+	s.mu.Lock()
+	s.lastAttempt = time.Now()
+	s.mu.Unlock()
+
+	data, err := s.load(ctx)
+	if err != nil {
+		return err
+	}
+	keys, err := Parse(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.source, err)
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("%s: no signing keys", s.source)
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+
+	ids := make([]string, 0, len(keys))
+	for id := range keys {
+		ids = append(ids, id)
+	}
+	s.logger.Debug("Signing keys loaded", zap.String("source", s.source), zap.Strings("kids", ids))
+
+	return nil
+}
+
+// Run refreshes the keys every interval until ctx is cancelled.
+func (s *Set) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Error("Failed to refresh signing keys", zap.String("source", s.source), zap.Error(err))
+			}
+		}
+	}
+}
+
+// Key returns the key with the given ID. An unknown ID triggers a refresh,
+// at most once a minute, in case the provider has rotated to a new key.
+func (s *Set) Key(ctx context.Context, kid string) (Key, error) {
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	// Another caller may have refreshed while this one waited
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+
+	s.mu.RLock()
+	due := time.Since(s.lastAttempt) >= minRefreshInterval
+	s.mu.RUnlock()
+
+	if due {
+		if err := s.refresh(ctx); err != nil {
+			s.logger.Warn("Failed to refresh signing keys", zap.String("source", s.source), zap.String("kid", kid), zap.Error(err))
+		}
+		if key, ok := s.lookup(kid); ok {
+			return key, nil
+		}
+	}
+
+	return Key{}, ErrKeyNotFound
+}
+
+// lookup finds a key by ID. A set holding a single key without an ID, as
+// loaded from a PEM file, matches any ID.
+func (s *Set) lookup(kid string) (Key, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if key, ok := s.keys[kid]; ok {
+		return key, true
+	}
+	if key, ok := s.keys[""]; ok && len(s.keys) == 1 {
+		return key, true
+	}
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
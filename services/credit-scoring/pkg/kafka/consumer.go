@@ -0,0 +1,180 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// Message is a record read from a topic.
+type Message = kafka.Message
+
+// Handler processes a single message. Returning an error triggers a retry
+// unless the error is wrapped with Permanent.
+type Handler func(ctx context.Context, msg Message) error
+
+type ConsumerConfig struct {
+	Brokers         []string
+	GroupID         string
+	Topics          []string
+	DeadLetterTopic string
+	MaxAttempts     int
+	InitialBackoff  time.Duration
+	MaxBackoff      time.Duration
+}
+
+// Consumer reads topics as part of a consumer group, retrying failed messages
+// with exponential backoff before moving them to a dead-letter topic.
+type Consumer struct {
+	reader   *kafka.Reader
+	producer *Producer
+	cfg      ConsumerConfig
+	logger   *zap.Logger
+}
+
+func NewConsumer(cfg ConsumerConfig, producer *Producer, logger *zap.Logger) (*Consumer, error) {
+	if cfg.GroupID == "" {
+		return nil, fmt.Errorf("consumer group ID is required")
+	}
+	if len(cfg.Topics) == 0 {
+		return nil, fmt.Errorf("at least one topic is required")
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = 500 * time.Millisecond
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 30 * time.Second
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     cfg.Brokers,
+		GroupID:     cfg.GroupID,
+		GroupTopics: cfg.Topics,
+		MinBytes:    1,
+		MaxBytes:    10e6,
+	})
+
+	return &Consumer{
+		reader:   reader,
+		producer: producer,
+		cfg:      cfg,
+		logger:   logger,
+	}, nil
+}
+
+// Run consumes messages until ctx is cancelled. Offsets are committed only
+// after a message has been handled or dead-lettered.
+func (c *Consumer) Run(ctx context.Context, handler Handler) error {
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to fetch message: %w", err)
+		}
+
+		if attempts, err := c.handle(ctx, msg, handler); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			c.logger.Error("Failed to process message",
+				zap.Error(err),
+				zap.String("topic", msg.Topic),
+				zap.Int64("offset", msg.Offset),
+				zap.Int("attempts", attempts),
+			)
+			if err := c.deadLetter(ctx, msg, err, attempts); err != nil {
+				// Leave the offset uncommitted so the message is redelivered.
+				return fmt.Errorf("failed to dead-letter message: %w", err)
+			}
+		}
+
+		if err := c.reader.CommitMessages(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to commit offset: %w", err)
+		}
+	}
+}
+
+// handle runs handler with retries and returns the number of attempts made.
+func (c *Consumer) handle(ctx context.Context, msg Message, handler Handler) (int, error) {
+	for attempt := 1; ; attempt++ {
+		err := handler(ctx, msg)
+		if err == nil {
+			return attempt, nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= c.cfg.MaxAttempts {
+			return attempt, err
+		}
+
+		backoff := c.backoff(attempt)
+		c.logger.Warn("Retrying message",
+			zap.Error(err),
+			zap.String("topic", msg.Topic),
+			zap.Int64("offset", msg.Offset),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+		)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		}
+	}
+}
+
+// backoff returns an exponential delay with full jitter for the given attempt.
+func (c *Consumer) backoff(attempt int) time.Duration {
+	d := c.cfg.InitialBackoff << (attempt - 1)
+	if d <= 0 || d > c.cfg.MaxBackoff {
+		d = c.cfg.MaxBackoff
+	}
+	return time.Duration(rand.Int63n(int64(d)) + 1)
+}
+
+func (c *Consumer) deadLetter(ctx context.Context, msg Message, cause error, attempts int) error {
+	if c.cfg.DeadLetterTopic == "" {
+		return cause
+	}
+
+	// Replayed messages keep the topic they originally failed on.
+	if original, ok := Header(msg, HeaderDLQOriginalTopic); ok {
+		msg.Topic = original
+	}
+
+	return c.producer.write(ctx, c.cfg.DeadLetterTopic, deadLetterMessage(msg, StageConsume, cause, attempts))
+}
+
+func (c *Consumer) Close() error {
+	return c.reader.Close()
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as non-retryable so the message goes straight to the
+// dead-letter topic.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
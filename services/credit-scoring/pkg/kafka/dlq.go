@@ -0,0 +1,278 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Headers attached to every dead-lettered message.
+const (
+	HeaderDLQStage             = "dlq-stage"
+	HeaderDLQError             = "dlq-error"
+	HeaderDLQAttempts          = "dlq-attempts"
+	HeaderDLQOriginalTopic     = "dlq-original-topic"
+	HeaderDLQOriginalPartition = "dlq-original-partition"
+	HeaderDLQOriginalOffset    = "dlq-original-offset"
+	HeaderDLQFailedAt          = "dlq-failed-at"
+)
+
+// Stages at which a message can fail.
+const (
+	StagePublish = "publish"
+	StageConsume = "consume"
+)
+
+// DeadLetter is a dead-lettered message along with its failure details.
+type DeadLetter struct {
+	Partition         int       `json:"partition"`
+	Offset            int64     `json:"offset"`
+	Stage             string    `json:"stage"`
+	OriginalTopic     string    `json:"originalTopic"`
+	OriginalPartition int       `json:"originalPartition"`
+	OriginalOffset    int64     `json:"originalOffset"`
+	Attempts          int       `json:"attempts"`
+	Error             string    `json:"error"`
+	FailedAt          time.Time `json:"failedAt"`
+	Key               string    `json:"key"`
+	Value             string    `json:"value"`
+}
+
+// ParseDeadLetter extracts the failure details from a dead-lettered message.
+func ParseDeadLetter(msg Message) DeadLetter {
+	dl := DeadLetter{
+		Partition:         msg.Partition,
+		Offset:            msg.Offset,
+		OriginalPartition: -1,
+		OriginalOffset:    -1,
+		Key:               string(msg.Key),
+		Value:             string(msg.Value),
+	}
+
+	for _, h := range msg.Headers {
+		value := string(h.Value)
+		switch h.Key {
+		case HeaderDLQStage:
+			dl.Stage = value
+		case HeaderDLQError:
+			dl.Error = value
+		case HeaderDLQAttempts:
+			dl.Attempts, _ = strconv.Atoi(value)
+		case HeaderDLQOriginalTopic:
+			dl.OriginalTopic = value
+		case HeaderDLQOriginalPartition:
+			dl.OriginalPartition, _ = strconv.Atoi(value)
+		case HeaderDLQOriginalOffset:
+			dl.OriginalOffset, _ = strconv.ParseInt(value, 10, 64)
+		case HeaderDLQFailedAt:
+			dl.FailedAt, _ = time.Parse(time.RFC3339Nano, value)
+		}
+	}
+
+	return dl
+}
+
+// Header returns the value of the named header, if present.
+func Header(msg Message, key string) (string, bool) {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value), true
+		}
+	}
+	return "", false
+}
+
+// deadLetterMessage wraps msg with headers describing why it failed.
+func deadLetterMessage(msg Message, stage string, cause error, attempts int) kafka.Message {
+	var headers []kafka.Header
+	for _, h := range msg.Headers {
+		if !strings.HasPrefix(h.Key, "dlq-") {
+			headers = append(headers, h)
+		}
+	}
+
+	partition, offset := strconv.Itoa(msg.Partition), strconv.FormatInt(msg.Offset, 10)
+	if stage == StagePublish {
+		partition, offset = "-1", "-1"
+	}
+
+	headers = append(headers,
+		kafka.Header{Key: HeaderDLQStage, Value: []byte(stage)},
+		kafka.Header{Key: HeaderDLQError, Value: []byte(cause.Error())},
+		kafka.Header{Key: HeaderDLQAttempts, Value: []byte(strconv.Itoa(attempts))},
+		kafka.Header{Key: HeaderDLQOriginalTopic, Value: []byte(msg.Topic)},
+		kafka.Header{Key: HeaderDLQOriginalPartition, Value: []byte(partition)},
+		kafka.Header{Key: HeaderDLQOriginalOffset, Value: []byte(offset)},
+		kafka.Header{Key: HeaderDLQFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339Nano))},
+	)
+
+	return kafka.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	}
+}
+
+// InspectDeadLetters reads up to limit messages from every partition of topic
+// without committing offsets. A limit of zero reads everything.
+func InspectDeadLetters(ctx context.Context, brokers []string, topic string, limit int) ([]DeadLetter, error) {
+	conn, err := dialAny(ctx, brokers, func(broker string) (*kafka.Conn, error) {
+		return kafka.DialContext(ctx, "tcp", broker)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to broker: %w", err)
+	}
+	partitions, err := conn.ReadPartitions(topic)
+	conn.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read partitions: %w", err)
+	}
+
+	var letters []DeadLetter
+	for _, p := range partitions {
+		leader, err := dialAny(ctx, brokers, func(broker string) (*kafka.Conn, error) {
+			return kafka.DialLeader(ctx, "tcp", broker, topic, p.ID)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to partition %d leader: %w", p.ID, err)
+		}
+		first, last, err := leader.ReadOffsets()
+		leader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read offsets for partition %d: %w", p.ID, err)
+		}
+		if first >= last {
+			continue
+		}
+
+		reader := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:   brokers,
+			Topic:     topic,
+			Partition: p.ID,
+			MaxBytes:  10e6,
+		})
+		if err := reader.SetOffset(first); err != nil {
+			reader.Close()
+			return nil, err
+		}
+
+		for offset := first; offset < last; offset++ {
+			if limit > 0 && len(letters) >= limit {
+				break
+			}
+			msg, err := reader.FetchMessage(ctx)
+			if err != nil {
+				reader.Close()
+				return nil, fmt.Errorf("failed to read partition %d: %w", p.ID, err)
+			}
+			letters = append(letters, ParseDeadLetter(msg))
+			offset = msg.Offset
+		}
+		reader.Close()
+	}
+
+	return letters, nil
+}
+
+// dialAny tries dial with each broker in turn until one connects.
+func dialAny(ctx context.Context, brokers []string, dial func(broker string) (*kafka.Conn, error)) (*kafka.Conn, error) {
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("at least one broker is required")
+	}
+
+	var errs []error
+	for _, broker := range brokers {
+		conn, err := dial(broker)
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", broker, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// Replayer moves dead-lettered messages back onto a topic for reprocessing.
+// Progress is tracked with a consumer group so each message is replayed once.
+type Replayer struct {
+	reader   *kafka.Reader
+	producer *Producer
+}
+
+func NewReplayer(brokers []string, topic, groupID string, producer *Producer) *Replayer {
+	return &Replayer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:  brokers,
+			GroupID:  groupID,
+			Topic:    topic,
+			MaxBytes: 10e6,
+		}),
+		producer: producer,
+	}
+}
+
+// Replay republishes dead letters until none arrive for idle or limit
+// messages have been replayed. target chooses the destination topic for
+// each message; returning an empty topic skips it. Messages dead-lettered
+// while publishing are converted to the destination topic's encoding, and
+// replay stops at the first that can't be.
+func (r *Replayer) Replay(ctx context.Context, limit int, idle time.Duration, target func(dl DeadLetter) string) (int, error) {
+	replayed := 0
+	for limit <= 0 || replayed < limit {
+		fetchCtx, cancel := context.WithTimeout(ctx, idle)
+		msg, err := r.reader.FetchMessage(fetchCtx)
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			break
+		}
+		if err != nil {
+			return replayed, fmt.Errorf("failed to fetch dead letter: %w", err)
+		}
+
+		dl := ParseDeadLetter(msg)
+		if topic := target(dl); topic != "" {
+			var headers []kafka.Header
+			if topic != dl.OriginalTopic {
+				// Keep the original topic so consumers can route replayed messages.
+				headers = append(headers, kafka.Header{Key: HeaderDLQOriginalTopic, Value: []byte(dl.OriginalTopic)})
+			}
+			for _, h := range msg.Headers {
+				if !strings.HasPrefix(h.Key, "dlq-") {
+					headers = append(headers, h)
+				}
+			}
+			replay := kafka.Message{
+				Key:     msg.Key,
+				Value:   msg.Value,
+				Headers: headers,
+			}
+			if dl.Stage == StagePublish {
+				// Left uncommitted, so a later replay retries it
+				if replay, err = r.producer.reencode(ctx, topic, replay); err != nil {
+					return replayed, fmt.Errorf("failed to replay offset %d: %w", msg.Offset, err)
+				}
+			}
+			if err := r.producer.write(ctx, topic, replay); err != nil {
+				return replayed, fmt.Errorf("failed to replay offset %d: %w", msg.Offset, err)
+			}
+			replayed++
+		}
+
+		if err := r.reader.CommitMessages(ctx, msg); err != nil {
+			return replayed, fmt.Errorf("failed to commit offset %d: %w", msg.Offset, err)
+		}
+	}
+
+	return replayed, nil
+}
+
+func (r *Replayer) Close() error {
+	return r.reader.Close()
+}
@@ -0,0 +1,63 @@
+package kafka
+
+import (
+	_ "embed"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var (
+	//go:embed schemas/credit_score_event.proto
+	creditScoreEventProto string
+
+	//go:embed schemas/credit_score_event.avsc
+	creditScoreEventAvro string
+)
+
+// CreditScoreEvent is published whenever a credit score is calculated or refreshed.
+type CreditScoreEvent struct {
+	EventType string    `json:"eventType"`
+	UserID    string    `json:"userId"`
+	Score     int       `json:"score"`
+	Grade     string    `json:"grade"`
+	Timestamp time.Time `json:"timestamp"`
+	// TenantID is the tenant the score belongs to
+	TenantID string `json:"tenantId"`
+}
+
+func (e *CreditScoreEvent) ProtoSchema() string {
+	return creditScoreEventProto
+}
+
+func (e *CreditScoreEvent) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, e.EventType)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendString(b, e.UserID)
+	b = protowire.AppendTag(b, 3, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(int32(e.Score)))
+	b = protowire.AppendTag(b, 4, protowire.BytesType)
+	b = protowire.AppendString(b, e.Grade)
+	b = protowire.AppendTag(b, 5, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(e.Timestamp.UnixMilli()))
+	b = protowire.AppendTag(b, 6, protowire.BytesType)
+	b = protowire.AppendString(b, e.TenantID)
+	return b, nil
+}
+
+func (e *CreditScoreEvent) AvroSchema() string {
+	return creditScoreEventAvro
+}
+
+func (e *CreditScoreEvent) MarshalAvro() ([]byte, error) {
+	var b []byte
+	b = appendAvroString(b, e.EventType)
+	b = appendAvroString(b, e.UserID)
+	b = appendAvroLong(b, int64(e.Score))
+	b = appendAvroString(b, e.Grade)
+	b = appendAvroLong(b, e.Timestamp.UnixMilli())
+	b = appendAvroString(b, e.TenantID)
+	return b, nil
+}
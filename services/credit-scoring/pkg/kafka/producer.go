@@ -2,36 +2,161 @@ package kafka
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sync"
 
 	"github.com/segmentio/kafka-go"
 )
 
+// headerContentType names the encoding of a message's value.
+const headerContentType = "content-type"
+
 type Producer struct {
+	brokers         []string
+	encoders        map[string]Encoder
+	events          map[string]func() interface{}
+	deadLetterTopic string
+
+	mu      sync.Mutex
 	writers map[string]*kafka.Writer
 }
 
 func NewProducer(brokers []string) (*Producer, error) {
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("at least one broker is required")
+	}
+
 	return &Producer{
-		writers: make(map[string]*kafka.Writer),
+		brokers:  brokers,
+		encoders: make(map[string]Encoder),
+		events:   make(map[string]func() interface{}),
+		writers:  make(map[string]*kafka.Writer),
 	}, nil
 }
 
+// UseEncoder sets the encoder for events published to topic. Topics without
+// an encoder are published as JSON.
+func (p *Producer) UseEncoder(topic string, encoder Encoder) {
+	p.encoders[topic] = encoder
+}
+
+// UseEventType sets the type of the events published to topic, so those
+// dead-lettered as JSON can be re-encoded with the topic's encoder on replay.
+// newEvent returns a pointer to a new, empty event.
+func (p *Producer) UseEventType(topic string, newEvent func() interface{}) {
+	p.events[topic] = newEvent
+}
+
+// UseDeadLetterTopic sets the topic that receives events which could not be
+// published. Dead-lettered events keep their original encoding unless the
+// encoding itself failed, in which case they are stored as JSON.
+func (p *Producer) UseDeadLetterTopic(topic string) {
+	p.deadLetterTopic = topic
+}
+
 func (p *Producer) Publish(ctx context.Context, topic string, message []byte) error {
+	return p.write(ctx, topic, kafka.Message{
+		Value: message,
+	})
+}
+
+// PublishEvent encodes event with the topic's encoder and publishes it under key.
+func (p *Producer) PublishEvent(ctx context.Context, topic, key string, event interface{}) error {
+	encoder := p.encoder(topic)
+
+	msg := kafka.Message{
+		Topic: topic,
+		Key:   []byte(key),
+	}
+
+	value, err := encoder.Encode(ctx, topic, event)
+	if err != nil {
+		err = fmt.Errorf("failed to encode event: %w", err)
+		if jsonValue, jsonErr := (JSONEncoder{}).Encode(ctx, topic, event); jsonErr == nil {
+			msg.Value = jsonValue
+			msg.Headers = []kafka.Header{{Key: headerContentType, Value: []byte(JSONEncoder{}.ContentType())}}
+			return p.deadLetter(ctx, msg, err)
+		}
+		return err
+	}
+
+	msg.Value = value
+	msg.Headers = []kafka.Header{{Key: headerContentType, Value: []byte(encoder.ContentType())}}
+
+	if err := p.write(ctx, topic, msg); err != nil {
+		return p.deadLetter(ctx, msg, err)
+	}
+	return nil
+}
+
+func (p *Producer) encoder(topic string) Encoder {
+	if encoder, ok := p.encoders[topic]; ok {
+		return encoder
+	}
+	return JSONEncoder{}
+}
+
+// reencode converts the value of a message dead-lettered while publishing to
+// topic to the topic's encoding. Messages whose encoding failed were stored
+// as JSON, which consumers of a Protobuf or Avro topic can't read.
+func (p *Producer) reencode(ctx context.Context, topic string, msg kafka.Message) (kafka.Message, error) {
+	encoder := p.encoder(topic)
+	contentType, _ := Header(msg, headerContentType)
+	if contentType == "" || contentType == encoder.ContentType() {
+		return msg, nil
+	}
+
+	newEvent, ok := p.events[topic]
+	if !ok || contentType != (JSONEncoder{}).ContentType() {
+		return msg, fmt.Errorf("cannot convert %s to %s for topic %s", contentType, encoder.ContentType(), topic)
+	}
+	event := newEvent()
+	if err := json.Unmarshal(msg.Value, event); err != nil {
+		return msg, fmt.Errorf("failed to decode event: %w", err)
+	}
+	value, err := encoder.Encode(ctx, topic, event)
+	if err != nil {
+		return msg, fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	msg.Value = value
+	for i, h := range msg.Headers {
+		if h.Key == headerContentType {
+			msg.Headers[i].Value = []byte(encoder.ContentType())
+		}
+	}
+	return msg, nil
+}
+
+// deadLetter records a publish failure and returns cause, annotated if the
+// dead-letter write failed as well.
+func (p *Producer) deadLetter(ctx context.Context, msg kafka.Message, cause error) error {
+	if p.deadLetterTopic == "" {
+		return cause
+	}
+	if err := p.write(ctx, p.deadLetterTopic, deadLetterMessage(msg, StagePublish, cause, 1)); err != nil {
+		return fmt.Errorf("%w (dead-letter failed: %v)", cause, err)
+	}
+	return cause
+}
+
+func (p *Producer) write(ctx context.Context, topic string, msg kafka.Message) error {
+	p.mu.Lock()
 	writer, exists := p.writers[topic]
 	if !exists {
 		writer = &kafka.Writer{
-			Addr:     kafka.TCP("localhost:9092"),
+			Addr:     kafka.TCP(p.brokers...),
 			Topic:    topic,
 			Balancer: &kafka.LeastBytes{},
 		}
 		p.writers[topic] = writer
 	}
+	p.mu.Unlock()
 
-	err := writer.WriteMessages(ctx, kafka.Message{
-		Value: message,
-	})
-	if err != nil {
+	// The writer sets the topic; messages must not carry one of their own.
+	msg.Topic = ""
+	if err := writer.WriteMessages(ctx, msg); err != nil {
 		return fmt.Errorf("failed to write message: %w", err)
 	}
 
@@ -0,0 +1,90 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type fakeRegistry struct{}
+
+func (fakeRegistry) Register(ctx context.Context, subject, schemaType, schema string) (int, error) {
+	return 7, nil
+}
+
+func TestProducerReencode(t *testing.T) {
+	ctx := context.Background()
+	event := &CreditScoreEvent{EventType: "score.calculated", UserID: "u1", Score: 700, Grade: "B", Timestamp: time.UnixMilli(1700000000000).UTC(), TenantID: "t1"}
+	jsonValue, _ := json.Marshal(event)
+	protoValue, _ := (&ProtobufEncoder{registry: fakeRegistry{}}).Encode(ctx, "events", event)
+
+	message := func(contentType string, value []byte) kafka.Message {
+		return kafka.Message{Value: value, Headers: []kafka.Header{{Key: headerContentType, Value: []byte(contentType)}}}
+	}
+
+	tests := []struct {
+		name      string
+		eventType bool
+		msg       kafka.Message
+		wantValue []byte
+		wantType  string
+		wantErr   bool
+	}{
+		{
+			name:      "JSON dead letter is re-encoded",
+			eventType: true,
+			msg:       message("application/json", jsonValue),
+			wantValue: protoValue,
+			wantType:  "application/x-protobuf",
+		},
+		{
+			name:      "already encoded message is unchanged",
+			eventType: true,
+			msg:       message("application/x-protobuf", protoValue),
+			wantValue: protoValue,
+			wantType:  "application/x-protobuf",
+		},
+		{
+			name:    "unknown event type is refused",
+			msg:     message("application/json", jsonValue),
+			wantErr: true,
+		},
+		{
+			name:      "other encodings are refused",
+			eventType: true,
+			msg:       message("application/vnd.apache.avro+binary", []byte{0}),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := NewProducer([]string{"localhost:9092"})
+			p.UseEncoder("events", &ProtobufEncoder{registry: fakeRegistry{}})
+			if tt.eventType {
+				p.UseEventType("events", func() interface{} { return &CreditScoreEvent{} })
+			}
+
+			got, err := p.reencode(ctx, "events", tt.msg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(got.Value, tt.wantValue) {
+				t.Errorf("value = %x, want %x", got.Value, tt.wantValue)
+			}
+			if contentType, _ := Header(got, headerContentType); contentType != tt.wantType {
+				t.Errorf("content type = %q, want %q", contentType, tt.wantType)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schema types understood by the registry.
+const (
+	SchemaTypeAvro     = "AVRO"
+	SchemaTypeProtobuf = "PROTOBUF"
+)
+
+// SchemaRegistry registers schemas under a subject and returns their global ID.
+// Registering an identical schema again returns the existing ID.
+type SchemaRegistry interface {
+	Register(ctx context.Context, subject, schemaType, schema string) (int, error)
+}
+
+// NewSchemaRegistry returns an HTTP client for the registry at url, or an
+// in-process stand-in when url is empty or "local".
+func NewSchemaRegistry(url string) SchemaRegistry {
+	if url == "" || url == "local" {
+		return NewLocalRegistry()
+	}
+	return NewRegistryClient(url)
+}
+
+// RegistryClient talks to a Confluent-compatible schema registry over HTTP.
+type RegistryClient struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mu  sync.RWMutex
+	ids map[string]int
+}
+
+func NewRegistryClient(baseURL string) *RegistryClient {
+	return &RegistryClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		ids:        make(map[string]int),
+	}
+}
+
+func (r *RegistryClient) Register(ctx context.Context, subject, schemaType, schema string) (int, error) {
+	cacheKey := subject + "\x00" + schema
+
+	r.mu.RLock()
+	id, ok := r.ids[cacheKey]
+	r.mu.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	body := map[string]string{"schema": schema}
+	// The registry treats a missing schemaType as AVRO.
+	if schemaType != SchemaTypeAvro {
+		body["schemaType"] = schemaType
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/subjects/%s/versions", r.baseURL, subject), bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reach schema registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var regErr struct {
+			ErrorCode int    `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&regErr)
+		return 0, fmt.Errorf("schema registry returned %d: %s", resp.StatusCode, regErr.Message)
+	}
+
+	var result struct {
+		ID int `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode registry response: %w", err)
+	}
+
+	r.mu.Lock()
+	r.ids[cacheKey] = result.ID
+	r.mu.Unlock()
+
+	return result.ID, nil
+}
+
+// LocalRegistry is an in-memory stand-in for the schema registry, useful for
+// local development and tests. IDs are only stable for the process lifetime.
+type LocalRegistry struct {
+	mu     sync.Mutex
+	ids    map[string]int
+	nextID int
+}
+
+func NewLocalRegistry() *LocalRegistry {
+	return &LocalRegistry{
+		ids:    make(map[string]int),
+		nextID: 1,
+	}
+}
+
+func (r *LocalRegistry) Register(ctx context.Context, subject, schemaType, schema string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Like the real registry, IDs are global and shared by identical schemas.
+	key := schemaType + "\x00" + schema
+	if id, ok := r.ids[key]; ok {
+		return id, nil
+	}
+
+	id := r.nextID
+	r.nextID++
+	r.ids[key] = id
+	return id, nil
+}
@@ -0,0 +1,28 @@
+package mtls
+
+import "crypto/x509"
+
+// dnsPrefix marks an identity that is a DNS SAN rather than a URI SAN
+const dnsPrefix = "dns:"
+
+// Identities maps client certificate identities to roles. An identity is a
+// URI SAN, normally a SPIFFE ID such as
+// "spiffe://cluster.local/ns/lending/sa/loan-origination", or "dns:" and a
+// DNS SAN.
+type Identities map[string]string
+
+// Identify returns the first of the certificate's SANs that is mapped to a
+// role, trying URI SANs before DNS SANs.
+func (ids Identities) Identify(cert *x509.Certificate) (identity, role string, ok bool) {
+	for _, uri := range cert.URIs {
+		if role, ok := ids[uri.String()]; ok {
+			return uri.String(), role, true
+		}
+	}
+	for _, name := range cert.DNSNames {
+		if role, ok := ids[dnsPrefix+name]; ok {
+			return dnsPrefix + name, role, true
+		}
+	}
+	return "", "", false
+}
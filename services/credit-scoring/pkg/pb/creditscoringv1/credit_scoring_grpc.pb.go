@@ -0,0 +1,241 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: creditscoring/v1/credit_scoring.proto
+
+package creditscoringv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CreditScoringService_CalculateScore_FullMethodName = "/creditscoring.v1.CreditScoringService/CalculateScore"
+	CreditScoringService_GetScore_FullMethodName       = "/creditscoring.v1.CreditScoringService/GetScore"
+	CreditScoringService_GetHistory_FullMethodName     = "/creditscoring.v1.CreditScoringService/GetHistory"
+	CreditScoringService_RefreshScore_FullMethodName   = "/creditscoring.v1.CreditScoringService/RefreshScore"
+)
+
+// CreditScoringServiceClient is the client API for CreditScoringService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CreditScoringService is the gRPC counterpart of /api/v1/credit. Calls must
+// carry an "authorization: Bearer <jwt>" metadata entry.
+type CreditScoringServiceClient interface {
+	CalculateScore(ctx context.Context, in *CalculateScoreRequest, opts ...grpc.CallOption) (*CreditScore, error)
+	GetScore(ctx context.Context, in *GetScoreRequest, opts ...grpc.CallOption) (*CreditScore, error)
+	GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error)
+	RefreshScore(ctx context.Context, in *RefreshScoreRequest, opts ...grpc.CallOption) (*CreditScore, error)
+}
+
+type creditScoringServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCreditScoringServiceClient(cc grpc.ClientConnInterface) CreditScoringServiceClient {
+	return &creditScoringServiceClient{cc}
+}
+
+func (c *creditScoringServiceClient) CalculateScore(ctx context.Context, in *CalculateScoreRequest, opts ...grpc.CallOption) (*CreditScore, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreditScore)
+	err := c.cc.Invoke(ctx, CreditScoringService_CalculateScore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *creditScoringServiceClient) GetScore(ctx context.Context, in *GetScoreRequest, opts ...grpc.CallOption) (*CreditScore, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreditScore)
+	err := c.cc.Invoke(ctx, CreditScoringService_GetScore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *creditScoringServiceClient) GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetHistoryResponse)
+	err := c.cc.Invoke(ctx, CreditScoringService_GetHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *creditScoringServiceClient) RefreshScore(ctx context.Context, in *RefreshScoreRequest, opts ...grpc.CallOption) (*CreditScore, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreditScore)
+	err := c.cc.Invoke(ctx, CreditScoringService_RefreshScore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreditScoringServiceServer is the server API for CreditScoringService service.
+// All implementations must embed UnimplementedCreditScoringServiceServer
+// for forward compatibility.
+//
+// CreditScoringService is the gRPC counterpart of /api/v1/credit. Calls must
+// carry an "authorization: Bearer <jwt>" metadata entry.
+type CreditScoringServiceServer interface {
+	CalculateScore(context.Context, *CalculateScoreRequest) (*CreditScore, error)
+	GetScore(context.Context, *GetScoreRequest) (*CreditScore, error)
+	GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error)
+	RefreshScore(context.Context, *RefreshScoreRequest) (*CreditScore, error)
+	mustEmbedUnimplementedCreditScoringServiceServer()
+}
+
+// UnimplementedCreditScoringServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCreditScoringServiceServer struct{}
+
+func (UnimplementedCreditScoringServiceServer) CalculateScore(context.Context, *CalculateScoreRequest) (*CreditScore, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CalculateScore not implemented")
+}
+func (UnimplementedCreditScoringServiceServer) GetScore(context.Context, *GetScoreRequest) (*CreditScore, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScore not implemented")
+}
+func (UnimplementedCreditScoringServiceServer) GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
+func (UnimplementedCreditScoringServiceServer) RefreshScore(context.Context, *RefreshScoreRequest) (*CreditScore, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshScore not implemented")
+}
+func (UnimplementedCreditScoringServiceServer) mustEmbedUnimplementedCreditScoringServiceServer() {}
+func (UnimplementedCreditScoringServiceServer) testEmbeddedByValue()                              {}
+
+// UnsafeCreditScoringServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CreditScoringServiceServer will
+// result in compilation errors.
+type UnsafeCreditScoringServiceServer interface {
+	mustEmbedUnimplementedCreditScoringServiceServer()
+}
+
+func RegisterCreditScoringServiceServer(s grpc.ServiceRegistrar, srv CreditScoringServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCreditScoringServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CreditScoringService_ServiceDesc, srv)
+}
+
+func _CreditScoringService_CalculateScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculateScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CreditScoringServiceServer).CalculateScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CreditScoringService_CalculateScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CreditScoringServiceServer).CalculateScore(ctx, req.(*CalculateScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CreditScoringService_GetScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CreditScoringServiceServer).GetScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CreditScoringService_GetScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CreditScoringServiceServer).GetScore(ctx, req.(*GetScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CreditScoringService_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CreditScoringServiceServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CreditScoringService_GetHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CreditScoringServiceServer).GetHistory(ctx, req.(*GetHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CreditScoringService_RefreshScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CreditScoringServiceServer).RefreshScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CreditScoringService_RefreshScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CreditScoringServiceServer).RefreshScore(ctx, req.(*RefreshScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CreditScoringService_ServiceDesc is the grpc.ServiceDesc for CreditScoringService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CreditScoringService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "creditscoring.v1.CreditScoringService",
+	HandlerType: (*CreditScoringServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CalculateScore",
+			Handler:    _CreditScoringService_CalculateScore_Handler,
+		},
+		{
+			MethodName: "GetScore",
+			Handler:    _CreditScoringService_GetScore_Handler,
+		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _CreditScoringService_GetHistory_Handler,
+		},
+		{
+			MethodName: "RefreshScore",
+			Handler:    _CreditScoringService_RefreshScore_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "creditscoring/v1/credit_scoring.proto",
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often keys whose limit has fully recovered are
+// dropped from a localLimiter
+const sweepInterval = time.Minute
+
+// localLimiter is the GCRA in process memory, used when Redis can't be.
+type localLimiter struct {
+	mu sync.Mutex
+	// tats holds each key's theoretical arrival time; keys whose TAT has
+	// passed are at their full burst and can be dropped
+	tats      map[string]time.Time
+	lastSweep time.Time
+}
+
+func newLocalLimiter() *localLimiter {
+	return &localLimiter{tats: make(map[string]time.Time)}
+}
+
+func (l *localLimiter) allow(key string, limit Limit, now time.Time) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > sweepInterval {
+		for k, tat := range l.tats {
+			if tat.Before(now) {
+				delete(l.tats, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	emission := limit.emission()
+	tolerance := emission * time.Duration(limit.Rate)
+
+	tat := l.tats[key]
+	if tat.Before(now) {
+		tat = now
+	}
+	newTAT := tat.Add(emission)
+	allowAt := newTAT.Add(-tolerance)
+	if allowAt.After(now) {
+		return Result{
+			Limit:      limit,
+			RetryAfter: allowAt.Sub(now),
+			ResetAfter: tat.Sub(now),
+		}
+	}
+
+	l.tats[key] = newTAT
+	return Result{
+		Limit:      limit,
+		Allowed:    true,
+		Remaining:  int(now.Sub(allowAt) / emission),
+		ResetAfter: newTAT.Sub(now),
+	}
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocalLimiterAllow(t *testing.T) {
+	limiter := newLocalLimiter()
+	limit := Limit{Rate: 3, Period: 3 * time.Second}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Steps run in order against the same limiter
+	steps := []struct {
+		name           string
+		key            string
+		at             time.Duration
+		wantAllowed    bool
+		wantRemaining  int
+		wantRetryAfter time.Duration
+		wantResetAfter time.Duration
+	}{
+		{"burst 1", "a", 0, true, 2, 0, time.Second},
+		{"burst 2", "a", 0, true, 1, 0, 2 * time.Second},
+		{"burst 3", "a", 0, true, 0, 0, 3 * time.Second},
+		{"burst spent", "a", 0, false, 0, time.Second, 3 * time.Second},
+		{"other key", "b", 0, true, 2, 0, time.Second},
+		{"before emission", "a", 500 * time.Millisecond, false, 0, 500 * time.Millisecond, 2500 * time.Millisecond},
+		{"after emission", "a", time.Second, true, 0, 0, 3 * time.Second},
+		{"one per emission", "a", time.Second, false, 0, time.Second, 3 * time.Second},
+		{"full burst again", "a", 10 * time.Second, true, 2, 0, time.Second},
+	}
+
+	for _, step := range steps {
+		result := limiter.allow(step.key, limit, start.Add(step.at))
+		if result.Allowed != step.wantAllowed ||
+			result.Remaining != step.wantRemaining ||
+			result.RetryAfter != step.wantRetryAfter ||
+			result.ResetAfter != step.wantResetAfter {
+			t.Fatalf("%s: got allowed=%v remaining=%d retry=%s reset=%s, want allowed=%v remaining=%d retry=%s reset=%s",
+				step.name, result.Allowed, result.Remaining, result.RetryAfter, result.ResetAfter,
+				step.wantAllowed, step.wantRemaining, step.wantRetryAfter, step.wantResetAfter)
+		}
+		if result.Limit != limit {
+			t.Fatalf("%s: limit = %v, want %v", step.name, result.Limit, limit)
+		}
+	}
+}
+
+func TestLocalLimiterSweep(t *testing.T) {
+	limiter := newLocalLimiter()
+	limit := Limit{Rate: 1, Period: time.Second}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	limiter.allow("a", limit, start)
+	limiter.allow("b", limit, start.Add(sweepInterval+2*time.Second))
+
+	if _, ok := limiter.tats["a"]; ok {
+		t.Error("recovered key was not swept")
+	}
+	if _, ok := limiter.tats["b"]; !ok {
+		t.Error("key in use was swept")
+	}
+}
@@ -0,0 +1,168 @@
+// Package ratelimit limits request rates across replicas with the generic
+// cell rate algorithm (GCRA), keeping each key's state in Redis. While Redis
+// is unreachable each replica falls back to limiting on its own.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"credit-scoring/pkg/redis"
+)
+
+// redisTimeout bounds how long a request waits on Redis before the local
+// limiter is used instead
+const redisTimeout = 100 * time.Millisecond
+
+// Limit allows Rate requests per Period, in bursts of up to Rate.
+type Limit struct {
+	Rate   int
+	Period time.Duration
+}
+
+// ParseLimit parses "<rate>/<period>", where period is a Go duration or a
+// bare unit: "100/s", "600/1m", "5000/1h".
+func ParseLimit(s string) (Limit, error) {
+	rate, period, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q is not <rate>/<period>", s)
+	}
+
+	n, err := strconv.Atoi(rate)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q must allow a positive number of requests", s)
+	}
+
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q has an invalid period", s)
+	}
+
+	return Limit{Rate: n, Period: d}, nil
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Rate, l.Period)
+}
+
+// emission is the interval at which requests are allowed once a burst is
+// spent
+func (l Limit) emission() time.Duration {
+	return l.Period / time.Duration(l.Rate)
+}
+
+// Result is the outcome of a request against a limit.
+type Result struct {
+	Limit   Limit
+	Allowed bool
+	// Remaining is how many more requests would be allowed right now
+	Remaining int
+	// RetryAfter is how long until a denied request would be allowed
+	RetryAfter time.Duration
+	// ResetAfter is how long until the full burst is available again
+	ResetAfter time.Duration
+}
+
+// gcraScript keeps the theoretical arrival time (TAT) of KEYS[1]'s next
+// request, in milliseconds. ARGV[1] is the emission interval and ARGV[2] the
+// burst tolerance, both in milliseconds. It returns allowed (0 or 1),
+// remaining, retry after and reset after.
+var gcraScript = redis.NewScript(`
+local emission = tonumber(ARGV[1])
+local tolerance = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = time[1] * 1000 + time[2] / 1000
+
+local tat = math.max(tonumber(redis.call('GET', KEYS[1]) or now), now)
+local new_tat = tat + emission
+local allow_at = new_tat - tolerance
+if allow_at > now then
+	return {0, 0, math.ceil(allow_at - now), math.ceil(tat - now)}
+end
+
+redis.call('SET', KEYS[1], string.format('%.3f', new_tat), 'PX', math.ceil(new_tat - now))
+return {1, math.floor((now - allow_at) / emission), 0, math.ceil(new_tat - now)}
+`)
+
+// scriptRunner is the part of the Redis client the limiter uses.
+type scriptRunner interface {
+	RunScript(ctx context.Context, script *redis.Script, keys []string, args ...interface{}) ([]int64, error)
+}
+
+// Limiter counts requests against limits shared by every replica using the
+// same Redis.
+type Limiter struct {
+	redis  scriptRunner
+	local  *localLimiter
+	logger *zap.Logger
+	// now is the local limiter's clock
+	now func() time.Time
+
+	// degraded is set while Redis is failing, so the switch to and from the
+	// local limiter is logged once rather than per request
+	degraded atomic.Bool
+}
+
+// New returns a limiter backed by client, or only by the local limiter if
+// client is nil.
+func New(client *redis.RedisClient, logger *zap.Logger) *Limiter {
+	l := &Limiter{
+		local:  newLocalLimiter(),
+		logger: logger,
+		now:    time.Now,
+	}
+	if client != nil {
+		l.redis = client
+	}
+	return l
+}
+
+// Allow counts a request for key against limit. Requests sharing a key share
+// a limit, so key should name both what is limited and who is calling.
+//
+// If Redis fails the request is counted by this replica alone until Redis
+// recovers, so limits are briefly per replica rather than shared.
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) Result {
+	key = "ratelimit:" + key
+	if l.redis == nil {
+		return l.local.allow(key, limit, l.now())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	emission := limit.emission()
+	reply, err := l.redis.RunScript(ctx, gcraScript, []string{key},
+		float64(emission)/float64(time.Millisecond),
+		float64(emission*time.Duration(limit.Rate))/float64(time.Millisecond),
+	)
+	if err == nil && len(reply) != 4 {
+		err = fmt.Errorf("unexpected rate limit reply %v", reply)
+	}
+	if err != nil {
+		if !l.degraded.Swap(true) {
+			l.logger.Warn("Rate limiting locally while Redis is unavailable", zap.Error(err))
+		}
+		return l.local.allow(key, limit, l.now())
+	}
+	if l.degraded.Swap(false) {
+		l.logger.Info("Rate limiting through Redis again")
+	}
+
+	return Result{
+		Limit:      limit,
+		Allowed:    reply[0] == 1,
+		Remaining:  int(reply[1]),
+		RetryAfter: time.Duration(reply[2]) * time.Millisecond,
+		ResetAfter: time.Duration(reply[3]) * time.Millisecond,
+	}
+}
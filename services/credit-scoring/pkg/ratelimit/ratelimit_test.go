@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"credit-scoring/pkg/redis"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Limit
+		wantErr bool
+	}{
+		{spec: "100/s", want: Limit{Rate: 100, Period: time.Second}},
+		{spec: "600/1m", want: Limit{Rate: 600, Period: time.Minute}},
+		{spec: "5000/1h", want: Limit{Rate: 5000, Period: time.Hour}},
+		{spec: "10/m", want: Limit{Rate: 10, Period: time.Minute}},
+		{spec: " 5/500ms ", want: Limit{Rate: 5, Period: 500 * time.Millisecond}},
+		{spec: "", wantErr: true},
+		{spec: "100", wantErr: true},
+		{spec: "0/s", wantErr: true},
+		{spec: "-1/s", wantErr: true},
+		{spec: "many/s", wantErr: true},
+		{spec: "10/", wantErr: true},
+		{spec: "10/0s", wantErr: true},
+		{spec: "10/-1m", wantErr: true},
+		{spec: "10/fortnight", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseLimit(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLimit(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLimit(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeScriptRunner struct {
+	reply []int64
+	err   error
+	keys  []string
+}
+
+func (f *fakeScriptRunner) RunScript(ctx context.Context, script *redis.Script, keys []string, args ...interface{}) ([]int64, error) {
+	f.keys = append(f.keys, keys...)
+	return f.reply, f.err
+}
+
+func TestLimiterAllow(t *testing.T) {
+	limit := Limit{Rate: 2, Period: time.Minute}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		redis *fakeScriptRunner
+		// want is the result of the last of three requests
+		want      Result
+		wantLocal bool
+	}{
+		{
+			name:  "redis",
+			redis: &fakeScriptRunner{reply: []int64{1, 4, 0, 1500}},
+			want:  Result{Limit: limit, Allowed: true, Remaining: 4, ResetAfter: 1500 * time.Millisecond},
+		},
+		{
+			name:  "redis denies",
+			redis: &fakeScriptRunner{reply: []int64{0, 0, 250, 60000}},
+			want:  Result{Limit: limit, RetryAfter: 250 * time.Millisecond, ResetAfter: time.Minute},
+		},
+		{
+			name:      "redis fails",
+			redis:     &fakeScriptRunner{err: stderrors.New("connection refused")},
+			want:      Result{Limit: limit, RetryAfter: 30 * time.Second, ResetAfter: time.Minute},
+			wantLocal: true,
+		},
+		{
+			name:      "unexpected reply",
+			redis:     &fakeScriptRunner{reply: []int64{1}},
+			want:      Result{Limit: limit, RetryAfter: 30 * time.Second, ResetAfter: time.Minute},
+			wantLocal: true,
+		},
+		{
+			name:      "no redis",
+			want:      Result{Limit: limit, RetryAfter: 30 * time.Second, ResetAfter: time.Minute},
+			wantLocal: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := New(nil, zap.NewNop())
+			limiter.now = func() time.Time { return now }
+			if tt.redis != nil {
+				limiter.redis = tt.redis
+			}
+
+			var result Result
+			for i := 0; i < 3; i++ {
+				result = limiter.Allow(context.Background(), "score:us_1", limit)
+			}
+
+			if result != tt.want {
+				t.Errorf("Allow() = %+v, want %+v", result, tt.want)
+			}
+			if _, ok := limiter.local.tats["ratelimit:score:us_1"]; ok != tt.wantLocal {
+				t.Errorf("counted locally = %v, want %v", ok, tt.wantLocal)
+			}
+			if tt.redis != nil && (len(tt.redis.keys) != 3 || tt.redis.keys[0] != "ratelimit:score:us_1") {
+				t.Errorf("redis keys = %v, want ratelimit:score:us_1 for every request", tt.redis.keys)
+			}
+			if limiter.degraded.Load() != (tt.wantLocal && tt.redis != nil) {
+				t.Errorf("degraded = %v", limiter.degraded.Load())
+			}
+		})
+	}
+}
+
+func TestLimiterRecovers(t *testing.T) {
+	redis := &fakeScriptRunner{err: stderrors.New("connection refused")}
+	limiter := New(nil, zap.NewNop())
+	limiter.redis = redis
+
+	limiter.Allow(context.Background(), "k", Limit{Rate: 1, Period: time.Second})
+	if !limiter.degraded.Load() {
+		t.Fatal("limiter not degraded while redis fails")
+	}
+
+	redis.err, redis.reply = nil, []int64{1, 0, 0, 1000}
+	if result := limiter.Allow(context.Background(), "k", Limit{Rate: 1, Period: time.Second}); !result.Allowed {
+		t.Errorf("Allow() = %+v after redis recovered", result)
+	}
+	if limiter.degraded.Load() {
+		t.Error("limiter still degraded after redis recovered")
+	}
+}
@@ -49,10 +49,106 @@ func (r *RedisClient) Get(ctx context.Context, key string, dest interface{}) err
 	return json.Unmarshal(data, dest)
 }
 
+// SetNX sets key only if it does not already exist and reports whether it was set.
+func (r *RedisClient) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	return r.client.SetNX(ctx, key, data, expiration).Result()
+}
+
 func (r *RedisClient) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
 }
 
+// compareAndDeleteScript deletes KEYS[1] only if it holds ARGV[1].
+var compareAndDeleteScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// DeleteIfEqual deletes key only if it still holds value, as set by Set or
+// SetNX, so a lock that expired and was taken by someone else isn't released.
+func (r *RedisClient) DeleteIfEqual(ctx context.Context, key string, value interface{}) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	deleted, err := compareAndDeleteScript.Run(ctx, r.client, []string{key}, data).Int()
+	return deleted == 1, err
+}
+
+// Exists returns how many of keys exist.
+func (r *RedisClient) Exists(ctx context.Context, keys ...string) (int64, error) {
+	return r.client.Exists(ctx, keys...).Result()
+}
+
+// HGetAll returns every field of the hash at key, or an empty map if there is
+// none.
+func (r *RedisClient) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return r.client.HGetAll(ctx, key).Result()
+}
+
+// SMembers returns the members of the set at key.
+func (r *RedisClient) SMembers(ctx context.Context, key string) ([]string, error) {
+	return r.client.SMembers(ctx, key).Result()
+}
+
+// Publish sends value as JSON to every subscriber of channel.
+func (r *RedisClient) Publish(ctx context.Context, channel string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	return r.client.Publish(ctx, channel, data).Err()
+}
+
+// Subscribe calls handle with the payload of each message published to channel
+// until ctx is cancelled. The connection is re-established automatically.
+func (r *RedisClient) Subscribe(ctx context.Context, channel string, handle func(payload []byte)) error {
+	pubsub := r.client.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	// Wait for the subscription to be confirmed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", channel, err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			handle([]byte(msg.Payload))
+		}
+	}
+}
+
+// Script is a Lua script that Redis runs atomically.
+type Script struct {
+	script *redis.Script
+}
+
+func NewScript(src string) *Script {
+	return &Script{script: redis.NewScript(src)}
+}
+
+// RunScript runs script, by SHA once Redis has it cached, and returns its
+// result as an array of integers.
+func (r *RedisClient) RunScript(ctx context.Context, script *Script, keys []string, args ...interface{}) ([]int64, error) {
+	return script.script.Run(ctx, r.client, keys, args...).Int64Slice()
+}
+
 func (r *RedisClient) Close() error {
 	return r.client.Close()
 }
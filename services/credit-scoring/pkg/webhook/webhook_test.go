@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"64:ff9b::a00:1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := publicAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("publicAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	for _, u := range []string{
+		"https://127.0.0.1/hooks",
+		"https://[::1]:8443/hooks",
+		"https://169.254.169.254/latest/meta-data",
+		"https://10.0.0.5/hooks",
+	} {
+		if err := CheckURL(context.Background(), u); !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("CheckURL(%s) = %v, want ErrForbiddenAddress", u, err)
+		}
+	}
+
+	if err := CheckURL(context.Background(), "https://93.184.216.34/hooks"); err != nil {
+		t.Errorf("CheckURL of a public address: %v", err)
+	}
+}
+
+func TestSenderRefusesPrivateAddresses(t *testing.T) {
+	received := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+		w.Write([]byte("internal secrets"))
+	}))
+	defer server.Close()
+
+	// As if the subscription's host had been rebound to a loopback address
+	// after it was created
+	result, err := NewSender(time.Second).Send(context.Background(), server.URL, "secret", "nt_1", "credit_score_calculated", []byte(`{}`))
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Fatalf("Send error = %v, want ErrForbiddenAddress", err)
+	}
+	if received || result.StatusCode != 0 {
+		t.Errorf("request reached the server")
+	}
+
+	// The same server is reachable when the address is allowed
+	allowAll := func(network, address string, c syscall.RawConn) error { return nil }
+	result, err = newSender(time.Second, allowAll).Send(context.Background(), server.URL, "secret", "nt_1", "credit_score_calculated", []byte(`{}`))
+	if err != nil || !result.OK() {
+		t.Fatalf("Send = %+v, %v; want a 2xx result", result, err)
+	}
+}